@@ -0,0 +1,48 @@
+// Command backfill-achievements awards badges for matches that were finished
+// before achievements existed, or before a rule was added. It is safe to run
+// repeatedly; badges players already have are kept.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/database"
+	"scoretracker/backend/internal/models"
+)
+
+func main() {
+	organizationID := flag.Uint("organization", 0, "only backfill this organization (default: all)")
+	flag.Parse()
+
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	var organizations []models.Organization
+	query := db.Order("id ASC")
+	if *organizationID != 0 {
+		query = query.Where("id = ?", *organizationID)
+	}
+	if err := query.Find(&organizations).Error; err != nil {
+		log.Fatal("Failed to fetch organizations:", err)
+	}
+
+	total := 0
+	for _, organization := range organizations {
+		awarded, err := achievements.AwardBadges(db, organization.ID)
+		if err != nil {
+			log.Fatalf("Failed to award badges for organization %d: %v", organization.ID, err)
+		}
+		log.Printf("Organization %d (%s): %d new badges", organization.ID, organization.Name, awarded)
+		total += awarded
+	}
+
+	log.Printf("Awarded %d badges in %d organizations", total, len(organizations))
+}
@@ -0,0 +1,61 @@
+// Command grant-owner makes a registered user owner of an organization. It
+// bootstraps organizations nobody can manage yet, such as the default
+// organization that adopts championships from before organizations.
+//
+//	grant-owner -email user@example.com -organization 1
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"scoretracker/backend/internal/database"
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func main() {
+	email := flag.String("email", "", "email address the user registered with")
+	organizationID := flag.Uint("organization", 0, "ID of the organization")
+	flag.Parse()
+	if *email == "" || *organizationID == 0 {
+		flag.Usage()
+		log.Fatal("-email and -organization are required")
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("Failed to run migrations:", err)
+	}
+
+	var user models.User
+	var organization models.Organization
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("email = ?", strings.ToLower(strings.TrimSpace(*email))).First(&user).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&organization, *organizationID).Error; err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "updated_at"}),
+		}).Create(&models.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         user.ID,
+			Role:           models.OrganizationRoleOwner,
+		}).Error
+	})
+	if err != nil {
+		log.Fatal("Failed to grant ownership:", err)
+	}
+
+	log.Printf("%s is owner of organization %d (%s)", user.Email, organization.ID, organization.Name)
+}
@@ -0,0 +1,109 @@
+// Command migrate manages the database schema.
+//
+//	migrate status    list all migrations and when they were applied
+//	migrate up        apply all pending migrations
+//	migrate down [N]  revert the N most recent migrations (default 1)
+//	migrate redo      revert and reapply the most recent migration
+//	migrate backfill-records
+//	                  compute the records of organizations that have none,
+//	                  e.g. after importing matches from before records
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"scoretracker/backend/internal/database"
+	"scoretracker/backend/internal/migrations"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/records"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: migrate status | up | down [N] | redo | backfill-records")
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	db, err := database.Connect()
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	runner := migrations.NewRunner(db)
+
+	switch flag.Arg(0) {
+	case "status":
+		statuses, err := runner.Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%4d  %-30s %s\n", status.Version, status.Name, applied)
+		}
+
+	case "up":
+		applied, err := runner.Up()
+		for _, migration := range applied {
+			fmt.Printf("Applied %d (%s)\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+
+	case "down":
+		n := 1
+		if flag.NArg() > 1 {
+			n, err = strconv.Atoi(flag.Arg(1))
+			if err != nil || n < 1 {
+				log.Fatal("N must be a positive number")
+			}
+		}
+		reverted, err := runner.Down(n)
+		for _, migration := range reverted {
+			fmt.Printf("Reverted %d (%s)\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case "redo":
+		migration, err := runner.Redo()
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("Redid %d (%s)\n", migration.Version, migration.Name)
+
+	case "backfill-records":
+		// Not a versioned migration, as it runs the current records code
+		var organizationIDs []uint
+		if err := db.Model(&models.Organization{}).
+			Where("id NOT IN (?)", db.Model(&models.Record{}).Select("organization_id")).
+			Pluck("id", &organizationIDs).Error; err != nil {
+			log.Fatal("Failed to find organizations without records:", err)
+		}
+		for _, organizationID := range organizationIDs {
+			if err := records.Recompute(db, organizationID); err != nil {
+				log.Fatalf("Failed to compute records for organization %d: %v", organizationID, err)
+			}
+		}
+		fmt.Printf("Computed records of %d organizations\n", len(organizationIDs))
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
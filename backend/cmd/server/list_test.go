@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/openapi"
+	"scoretracker/backend/internal/service"
+)
+
+// TestListsHavePages makes sure lists without limit are paged, by the
+// default size or, for version 1 clients, by the largest page
+func TestListsHavePages(t *testing.T) {
+	db, tokens, router := newTestRouter(t, "list_test")
+	tenant := newTenant(t, db, tokens, "Alpha")
+
+	players := make([]models.Player, service.MaxPageSize+10)
+	for i := range players {
+		players[i] = models.Player{OrganizationID: tenant.organization.ID, Name: fmt.Sprintf("Extra %d", i)}
+	}
+	if err := db.Create(&players).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for version, want := range map[int]int{1: service.MaxPageSize, 2: service.DefaultPageSize} {
+		path := openapi.Prefix(version) + "/players"
+		response := serve(router, tenant.token, tenant.organization.ID, http.MethodGet, path, "")
+		if response.Code != http.StatusOK {
+			t.Fatalf("GET %s: got %d, want 200: %s", path, response.Code, response.Body)
+		}
+
+		var page []json.RawMessage
+		if err := json.Unmarshal(response.Body.Bytes(), &page); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		if len(page) != want {
+			t.Errorf("GET %s: got %d players, want %d", path, len(page), want)
+		}
+		if response.Header().Get("Link") == "" {
+			t.Errorf("GET %s: no link to the next page", path)
+		}
+	}
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"crypto/rand"
 	"log"
 	"os"
 	"time"
 
+	"scoretracker/backend/internal/auth"
 	"scoretracker/backend/internal/database"
-	"scoretracker/backend/internal/handlers"
+	"scoretracker/backend/internal/jobs"
 	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -19,59 +23,36 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	if err := database.Migrate(db); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	// Set MIGRATE_ON_START=false to apply migrations with the migrate command instead
+	if os.Getenv("MIGRATE_ON_START") != "false" {
+		if err := database.Migrate(db); err != nil {
+			log.Fatal("Failed to run migrations:", err)
+		}
 	}
 
-	router := gin.Default()
+	go jobs.RunAutoConfirm(db, time.Minute)
+
+	router := gin.New()
+	router.Use(gin.LoggerWithFormatter(middleware.LogFormatter), gin.CustomRecovery(problem.Recover))
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(problem.NoRoute)
+	router.NoMethod(problem.NoMethod)
 
 	// CORS configuration - allow all origins for development
 	// Safari requires specific headers and configuration
 	router.Use(cors.New(cors.Config{
 		AllowAllOrigins:  true, // Allow all origins for development
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "HEAD", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Access-Control-Allow-Origin"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.OrganizationHeader, "If-Match", "X-Requested-With", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "ETag", "Link", "X-Total-Count", "Deprecation", "Sunset", "Access-Control-Allow-Origin"},
 		AllowCredentials: false, // Safari has issues with credentials and AllowAllOrigins
 		MaxAge:           12 * time.Hour,
 	}))
 
 	router.Use(middleware.Logger())
 
-	router.GET("/api/health", handlers.HealthCheck)
-
-	matchHandler := handlers.NewMatchHandler(db)
-	championshipHandler := handlers.NewChampionshipHandler(db)
-	playerHandler := handlers.NewPlayerHandler(db)
-	
-	api := router.Group("/api")
-	{
-		// Championships
-		api.GET("/championships", championshipHandler.GetAllChampionships)
-		api.POST("/championships", championshipHandler.CreateChampionship)
-		api.GET("/championships/:id", championshipHandler.GetChampionship)
-		api.PUT("/championships/:id", championshipHandler.UpdateChampionship)
-		api.DELETE("/championships/:id", championshipHandler.DeleteChampionship)
-		api.POST("/championships/:id/finalize", championshipHandler.FinalizeChampionship)
-		api.GET("/championships/:id/standings", championshipHandler.GetStandings)
-
-		// Players
-		api.GET("/players", playerHandler.GetAllPlayers)
-		api.POST("/players", playerHandler.CreatePlayer)
-		api.GET("/players/:id", playerHandler.GetPlayer)
-		api.PUT("/players/:id", playerHandler.UpdatePlayer)
-		api.DELETE("/players/:id", playerHandler.DeletePlayer)
-
-		// Matches
-		api.GET("/matches", matchHandler.GetAllMatches)
-		api.POST("/matches", matchHandler.CreateMatch)
-		api.GET("/matches/:id", matchHandler.GetMatch)
-		// DELETE endpoint removed - matches should not be deletable
-		api.POST("/championships/:id/generate-matches", matchHandler.GenerateRoundRobinMatches)
-		api.POST("/matches/:id/start", matchHandler.StartMatch)
-		api.PUT("/matches/:id/score", matchHandler.UpdateMatchScore)
-		api.POST("/matches/:id/finish", matchHandler.FinishMatch)
-	}
+	tokens := auth.NewTokenService(loadJWTSecret())
+	newRoutes(db, tokens).mount(router, loadSunset())
 
 	port := os.Getenv("API_PORT")
 	if port == "" {
@@ -84,3 +65,35 @@ func main() {
 	}
 }
 
+// v1DeprecatedAt is when version 2 of the API was released
+var v1DeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// loadSunset reads API_V1_SUNSET, the date (YYYY-MM-DD) after which version 1
+// of the API is turned off. Without it no sunset is announced.
+func loadSunset() time.Time {
+	value := os.Getenv("API_V1_SUNSET")
+	if value == "" {
+		return time.Time{}
+	}
+
+	sunset, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		log.Fatal("Invalid API_V1_SUNSET, use YYYY-MM-DD:", err)
+	}
+	return sunset
+}
+
+// loadJWTSecret reads JWT_SECRET. Without it a random secret is generated,
+// which invalidates all issued tokens on restart.
+func loadJWTSecret() []byte {
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+
+	log.Println("Warning: JWT_SECRET is not set, using a random secret")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal("Failed to generate JWT secret:", err)
+	}
+	return secret
+}
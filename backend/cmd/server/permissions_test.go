@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/openapi"
+)
+
+// TestUnassignedPlayerNeedsAdmin makes sure that players outside every
+// championship can only be managed by admins of the organization or by the
+// user linked to them
+func TestUnassignedPlayerNeedsAdmin(t *testing.T) {
+	db, tokens, router := newTestRouter(t, "permissions_test")
+	tenant := newTenant(t, db, tokens, "Alpha")
+
+	player := models.Player{OrganizationID: tenant.organization.ID, Name: "Unassigned"}
+	if err := db.Create(&player).Error; err != nil {
+		t.Fatal(err)
+	}
+	weekday := 1
+	unavailability := models.PlayerUnavailability{PlayerID: player.ID, Kind: models.UnavailabilityKindWeekday, Weekday: &weekday}
+	if err := db.Create(&unavailability).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	member := models.User{Email: "member@example.com", PasswordHash: "-"}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.OrganizationMember{OrganizationID: tenant.organization.ID, UserID: member.ID, Role: models.OrganizationRoleMember}).Error; err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := tokens.IssueAccessToken(member.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := openapi.Prefix(openapi.Latest)
+	requests := []struct{ method, path, body string }{
+		{http.MethodPut, "/players/%d", `{"name": "Taken"}`},
+		{http.MethodPost, "/players/%d/unavailability", `{"kind": "weekday", "weekday": 2}`},
+		{http.MethodDelete, fmt.Sprintf("/players/%%d/unavailability/%d", unavailability.ID), ""},
+		{http.MethodDelete, "/players/%d", ""},
+	}
+	for _, r := range requests {
+		path := prefix + fmt.Sprintf(r.path, player.ID)
+		if response := serve(router, token, tenant.organization.ID, r.method, path, r.body); response.Code != http.StatusForbidden {
+			t.Errorf("member %s %s: got %d, want 403: %s", r.method, path, response.Code, response.Body)
+		}
+	}
+
+	path := prefix + fmt.Sprintf("/players/%d", player.ID)
+	if response := serve(router, tenant.token, tenant.organization.ID, http.MethodPut, path, `{"name": "Renamed"}`); response.Code != http.StatusOK {
+		t.Errorf("owner PUT %s: got %d, want 200: %s", path, response.Code, response.Body)
+	}
+
+	// Linking the member to the player lets them manage themselves
+	if err := db.Model(&member).Update("player_id", player.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if response := serve(router, token, tenant.organization.ID, http.MethodPut, path, `{"name": "Linked"}`); response.Code != http.StatusOK {
+		t.Errorf("linked member PUT %s: got %d, want 200: %s", path, response.Code, response.Body)
+	}
+}
+
+// TestChampionshipMembersNeedOrganizer makes sure the email addresses of
+// championship members are hidden from viewers and API keys
+func TestChampionshipMembersNeedOrganizer(t *testing.T) {
+	db, tokens, router := newTestRouter(t, "members_test")
+	tenant := newTenant(t, db, tokens, "Alpha")
+
+	viewer := models.User{Email: "viewer@example.com", PasswordHash: "-"}
+	if err := db.Create(&viewer).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.OrganizationMember{OrganizationID: tenant.organization.ID, UserID: viewer.ID, Role: models.OrganizationRoleMember}).Error; err != nil {
+		t.Fatal(err)
+	}
+	token, _, err := tokens.IssueAccessToken(viewer.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, keyPrefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.APIKey{
+		ChampionshipID:  tenant.championship.ID,
+		Name:            "Feed",
+		Prefix:          keyPrefix,
+		KeyHash:         hash,
+		Scope:           models.APIKeyScopeRead,
+		CreatedByUserID: tenant.owner.ID,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	path := openapi.Prefix(openapi.Latest) + fmt.Sprintf("/championships/%d/members", tenant.championship.ID)
+	for name, credentials := range map[string]string{"viewer": token, "api key": key} {
+		if response := serve(router, credentials, tenant.organization.ID, http.MethodGet, path, ""); response.Code != http.StatusForbidden {
+			t.Errorf("%s GET %s: got %d, want 403: %s", name, path, response.Code, response.Body)
+		}
+	}
+	if response := serve(router, tenant.token, tenant.organization.ID, http.MethodGet, path, ""); response.Code != http.StatusOK {
+		t.Errorf("owner GET %s: got %d, want 200: %s", path, response.Code, response.Body)
+	}
+}
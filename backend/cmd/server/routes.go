@@ -0,0 +1,194 @@
+package main
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/handlers"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// routes registers the endpoints of the API. Every version is served by the
+// same handlers, which adapt their responses to the version of the request.
+type routes struct {
+	db     *gorm.DB
+	tokens *auth.TokenService
+
+	matchHandler        *handlers.MatchHandler
+	championshipHandler *handlers.ChampionshipHandler
+	playerHandler       *handlers.PlayerHandler
+	authHandler         *handlers.AuthHandler
+	organizationHandler *handlers.OrganizationHandler
+	seasonHandler       *handlers.SeasonHandler
+	recordHandler       *handlers.RecordHandler
+}
+
+func newRoutes(db *gorm.DB, tokens *auth.TokenService) *routes {
+	return &routes{
+		db:                  db,
+		tokens:              tokens,
+		matchHandler:        handlers.NewMatchHandler(db),
+		championshipHandler: handlers.NewChampionshipHandler(db),
+		playerHandler:       handlers.NewPlayerHandler(db),
+		authHandler:         handlers.NewAuthHandler(db, tokens),
+		organizationHandler: handlers.NewOrganizationHandler(db),
+		seasonHandler:       handlers.NewSeasonHandler(db),
+		recordHandler:       handlers.NewRecordHandler(db),
+	}
+}
+
+// mount registers the routes of every version of the API, and of version 1
+// without prefix. Older versions keep their response shapes and announce the
+// latest one until the sunset, if any.
+func (r *routes) mount(router *gin.Engine, sunset time.Time) {
+	latest := openapi.Prefix(openapi.Latest)
+	for _, version := range openapi.Versions {
+		prefix := openapi.Prefix(version)
+		group := router.Group(prefix, middleware.APIVersion(version))
+		if version < openapi.Latest {
+			group.Use(middleware.Deprecated(prefix, latest, v1DeprecatedAt, sunset))
+		}
+		r.register(group)
+	}
+
+	// Clients from before versioning call version 1 without its prefix
+	legacy := router.Group(openapi.BasePath, middleware.APIVersion(1),
+		middleware.Deprecated(openapi.BasePath, latest, v1DeprecatedAt, sunset))
+	r.register(legacy)
+}
+
+func (r *routes) register(api *gin.RouterGroup) {
+	db := r.db
+	matchHandler := r.matchHandler
+	championshipHandler := r.championshipHandler
+	playerHandler := r.playerHandler
+	authHandler := r.authHandler
+	organizationHandler := r.organizationHandler
+	seasonHandler := r.seasonHandler
+	recordHandler := r.recordHandler
+
+	api.GET("/health", handlers.HealthCheck)
+	api.GET("/openapi.json", handlers.GetOpenAPI)
+	api.GET("/docs", handlers.GetDocs)
+
+	// Authentication
+	api.POST("/auth/register", authHandler.Register)
+	api.POST("/auth/login", authHandler.Login)
+	api.POST("/auth/refresh", authHandler.Refresh)
+	api.POST("/auth/logout", authHandler.Logout)
+
+	// Role checks scoped to the championship given by the :id route parameter
+	championshipOwner := middleware.RequireChampionshipRole(db, models.ChampionshipRoleOwner)
+	championshipOrganizer := middleware.RequireChampionshipRole(db, models.ChampionshipRoleOrganizer)
+	championshipViewer := middleware.RequireChampionshipRole(db, models.ChampionshipRoleViewer)
+	matchViewer := middleware.RequireMatchRole(db, models.ChampionshipRoleViewer)
+	matchPlayer := middleware.RequireMatchRole(db, models.ChampionshipRolePlayer)
+	matchOrganizer := middleware.RequireMatchRole(db, models.ChampionshipRoleOrganizer)
+
+	// Endpoints of the user account that are not tied to an organization
+	account := api.Group("")
+	account.Use(middleware.RequireAuth(db, r.tokens), middleware.RequireUser())
+	{
+		account.GET("/me", authHandler.GetCurrentUser)
+		account.PUT("/me", authHandler.UpdateCurrentUser)
+		account.GET("/me/matches", authHandler.GetMyMatches)
+
+		account.GET("/organizations", organizationHandler.GetMyOrganizations)
+		account.POST("/organizations", organizationHandler.CreateOrganization)
+		account.GET("/organizations/:id/members", organizationHandler.GetMembers)
+		account.PUT("/organizations/:id/members", organizationHandler.SetMember)
+		account.DELETE("/organizations/:id/members/:userId", organizationHandler.RemoveMember)
+	}
+
+	// Everything below is scoped to the caller's organization and accepts user JWTs and API keys.
+	// API keys only read their own championship: role checks fail for other championships,
+	// and the handlers filter lists to the key's championship.
+	tenant := api.Group("")
+	tenant.Use(middleware.RequireAuth(db, r.tokens), middleware.RequireOrganization(db))
+	{
+		tenant.GET("/championships/:id", championshipViewer, championshipHandler.GetChampionship)
+		tenant.GET("/championships/:id/standings", championshipViewer, championshipHandler.GetStandings)
+		tenant.GET("/championships/:id/standings/history", championshipViewer, championshipHandler.GetStandingsHistory)
+		tenant.GET("/championships/:id/projections", championshipViewer, championshipHandler.GetProjections)
+		tenant.GET("/championships/:id/draw", championshipViewer, championshipHandler.GetDraw)
+		tenant.GET("/players", playerHandler.GetAllPlayers)
+		tenant.GET("/matches", matchHandler.GetAllMatches)
+		tenant.GET("/matches/:id", matchViewer, matchHandler.GetMatch)
+
+		tenant.POST("/matches/:id/start", matchPlayer, matchHandler.StartMatch)
+		tenant.PUT("/matches/:id/score", matchPlayer, matchHandler.UpdateMatchScore)
+		tenant.POST("/matches/:id/finish", matchPlayer, matchHandler.FinishMatch)
+	}
+
+	// Calendar feeds, which also accept API keys as query parameter
+	feeds := api.Group("")
+	feeds.Use(middleware.RequireFeedAuth(db, r.tokens), middleware.RequireOrganization(db))
+	{
+		feeds.GET("/championships/:id/calendar.ics", championshipViewer, championshipHandler.GetChampionshipCalendar)
+		feeds.GET("/players/:id/calendar.ics", playerHandler.GetPlayerCalendar)
+	}
+
+	// Accepts user JWTs only
+	users := tenant.Group("")
+	users.Use(middleware.RequireUser())
+	{
+		// Reads spanning several championships of the organization
+		users.GET("/championships", championshipHandler.GetAllChampionships)
+		users.GET("/players/:id", playerHandler.GetPlayer)
+		users.GET("/players/:id/stats", playerHandler.GetPlayerStats)
+		users.GET("/players/:id/vs/:otherId", playerHandler.GetHeadToHead)
+		users.GET("/players/:id/achievements", playerHandler.GetAchievements)
+		users.GET("/players/:id/unavailability", playerHandler.GetUnavailability)
+		users.GET("/badges", playerHandler.GetBadges)
+		users.GET("/records", recordHandler.GetRecords)
+		users.GET("/seasons", seasonHandler.GetAllSeasons)
+		users.GET("/seasons/:id", seasonHandler.GetSeason)
+		users.GET("/seasons/:id/standings", seasonHandler.GetSeasonStandings)
+
+		// Championships
+		users.POST("/championships", championshipHandler.CreateChampionship)
+		users.PUT("/championships/:id", championshipOrganizer, championshipHandler.UpdateChampionship)
+		users.DELETE("/championships/:id", championshipOwner, championshipHandler.DeleteChampionship)
+		users.POST("/championships/:id/finalize", championshipOrganizer, championshipHandler.FinalizeChampionship)
+		users.GET("/championships/:id/members", championshipOrganizer, championshipHandler.GetMembers)
+		users.PUT("/championships/:id/members", championshipOrganizer, championshipHandler.SetMember)
+		users.DELETE("/championships/:id/members/:userId", championshipOrganizer, championshipHandler.RemoveMember)
+		users.GET("/championships/:id/api-keys", championshipOrganizer, championshipHandler.GetAPIKeys)
+		users.POST("/championships/:id/api-keys", championshipOrganizer, championshipHandler.CreateAPIKey)
+		users.DELETE("/championships/:id/api-keys/:keyId", championshipOrganizer, championshipHandler.RevokeAPIKey)
+
+		// Players - permissions depend on the player's championships and are checked in the handlers
+		users.POST("/players", playerHandler.CreatePlayer)
+		users.PUT("/players/:id", playerHandler.UpdatePlayer)
+		users.DELETE("/players/:id", playerHandler.DeletePlayer)
+		users.PUT("/players/:id/user", playerHandler.LinkUser)
+		users.DELETE("/players/:id/user", playerHandler.UnlinkUser)
+		users.POST("/players/:id/unavailability", playerHandler.CreateUnavailability)
+		users.DELETE("/players/:id/unavailability/:unavailabilityId", playerHandler.DeleteUnavailability)
+
+		// Matches
+		users.POST("/matches", matchHandler.CreateMatch)
+		// DELETE endpoint removed - matches should not be deletable
+		users.POST("/championships/:id/generate-matches", championshipOrganizer, matchHandler.GenerateRoundRobinMatches)
+		users.POST("/championships/:id/draw/preview", championshipOrganizer, matchHandler.PreviewDraw)
+		users.POST("/championships/:id/schedule", championshipOrganizer, matchHandler.ScheduleMatches)
+
+		// Seasons - managed by organization admins, checked in the handlers
+		users.POST("/seasons", seasonHandler.CreateSeason)
+		users.PUT("/seasons/:id", seasonHandler.UpdateSeason)
+		users.DELETE("/seasons/:id", seasonHandler.DeleteSeason)
+		users.PUT("/seasons/:id/championships/:championshipId", seasonHandler.AddChampionship)
+		users.DELETE("/seasons/:id/championships/:championshipId", seasonHandler.RemoveChampionship)
+
+		// Result confirmation for self-reported matches
+		users.POST("/matches/:id/confirm", matchPlayer, matchHandler.ConfirmResult)
+		users.POST("/matches/:id/dispute", matchPlayer, matchHandler.DisputeResult)
+		users.POST("/matches/:id/resolve", matchOrganizer, matchHandler.ResolveDispute)
+		users.GET("/championships/:id/disputes", championshipOrganizer, matchHandler.GetDisputes)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/database"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/openapi/openapitest"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRouter mounts the routes on a migrated in-memory SQLite database
+// of the given name
+func newTestRouter(t *testing.T, name string) (*gorm.DB, *auth.TokenService, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.OpenSQLite("file:"+name+"?mode=memory&cache=shared", &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens := auth.NewTokenService([]byte("test secret"))
+	router := gin.New()
+	router.Use(gin.CustomRecovery(problem.Recover))
+	router.HandleMethodNotAllowed = true
+	newRoutes(db, tokens).mount(router, time.Time{})
+	return db, tokens, router
+}
+
+// TestRoutesMatchSpecification makes sure every route is documented with the
+// access it has, so the specification cannot fall behind
+func TestRoutesMatchSpecification(t *testing.T) {
+	db, tokens, router := newTestRouter(t, "routes_test")
+
+	user := models.User{Email: "user@example.com", PasswordHash: "-"}
+	organization := models.Organization{Name: "Organization"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&organization).Error; err != nil {
+		t.Fatal(err)
+	}
+	championship := models.Championship{OrganizationID: organization.ID, Name: "Championship"}
+	if err := db.Create(&championship).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.APIKey{
+		ChampionshipID:  championship.ID,
+		Name:            "Feed",
+		Prefix:          prefix,
+		KeyHash:         hash,
+		Scope:           models.APIKeyScopeRead,
+		CreatedByUserID: user.ID,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	token, _, err := tokens.IssueAccessToken(user.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := openapitest.Verify(router, openapitest.Credentials{UserToken: token, APIKey: key}); err != nil {
+		t.Fatal(err)
+	}
+}
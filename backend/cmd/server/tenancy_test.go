@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// serve sends a request with the credentials and organization of the caller
+func serve(router *gin.Engine, token string, organizationID uint, method string, path string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.Header.Set(middleware.OrganizationHeader, strconv.FormatUint(uint64(organizationID), 10))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response
+}
+
+// tenant is an organization with an owner and one record of each kind
+type tenant struct {
+	organization models.Organization
+	owner        models.User
+	token        string
+	championship models.Championship
+	players      []models.Player
+	match        models.Match
+	season       models.Season
+}
+
+func newTenant(t *testing.T, db *gorm.DB, tokens *auth.TokenService, name string) tenant {
+	t.Helper()
+	create := func(value interface{}) {
+		t.Helper()
+		if err := db.Create(value).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tenant tenant
+	tenant.organization = models.Organization{Name: name}
+	create(&tenant.organization)
+	tenant.owner = models.User{Email: strings.ToLower(name) + "@example.com", PasswordHash: "-"}
+	create(&tenant.owner)
+	create(&models.OrganizationMember{OrganizationID: tenant.organization.ID, UserID: tenant.owner.ID, Role: models.OrganizationRoleOwner})
+
+	tenant.season = models.Season{OrganizationID: tenant.organization.ID, Name: name + " Season"}
+	create(&tenant.season)
+	tenant.championship = models.Championship{OrganizationID: tenant.organization.ID, Name: name + " Cup", SeasonID: &tenant.season.ID}
+	create(&tenant.championship)
+	for i := 1; i <= 2; i++ {
+		player := models.Player{
+			OrganizationID: tenant.organization.ID,
+			Name:           fmt.Sprintf("%s Player %d", name, i),
+			Championships:  []models.Championship{tenant.championship},
+		}
+		create(&player)
+		tenant.players = append(tenant.players, player)
+	}
+	tenant.match = models.Match{
+		OrganizationID: tenant.organization.ID,
+		ChampionshipID: tenant.championship.ID,
+		Player1:        tenant.players[0].Name,
+		Player2:        tenant.players[1].Name,
+		Game:           tenant.championship.Name,
+		Status:         models.MatchStatusPending,
+	}
+	create(&tenant.match)
+
+	token, _, err := tokens.IssueAccessToken(tenant.owner.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tenant.token = token
+	return tenant
+}
+
+// TestOrganizationsAreIsolated makes sure the owner of one organization, even
+// an admin, can neither see nor change the championships, players, matches and
+// seasons of another one
+func TestOrganizationsAreIsolated(t *testing.T) {
+	db, tokens, router := newTestRouter(t, "tenancy_test")
+	a := newTenant(t, db, tokens, "Alpha")
+	b := newTenant(t, db, tokens, "Beta")
+	// The admin flag grants no access to other organizations
+	if err := db.Model(&a.owner).Update("is_admin", true).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range openapi.Versions {
+		prefix := openapi.Prefix(version)
+
+		t.Run(fmt.Sprintf("v%d records", version), func(t *testing.T) {
+			requests := []struct{ method, path, body string }{
+				{http.MethodGet, "/championships/%[1]d", ""},
+				{http.MethodGet, "/championships/%[1]d/standings", ""},
+				{http.MethodGet, "/championships/%[1]d/draw", ""},
+				{http.MethodPut, "/championships/%[1]d", `{"name": "Taken"}`},
+				{http.MethodPost, "/championships/%[1]d/finalize", ""},
+				{http.MethodDelete, "/championships/%[1]d", ""},
+				{http.MethodGet, "/players/%[2]d", ""},
+				{http.MethodGet, "/players/%[2]d/stats", ""},
+				{http.MethodGet, "/players/%[2]d/vs/%[3]d", ""},
+				{http.MethodPut, "/players/%[2]d", `{"name": "Taken"}`},
+				{http.MethodDelete, "/players/%[2]d", ""},
+				{http.MethodGet, "/matches/%[4]d", ""},
+				{http.MethodPost, "/matches/%[4]d/start", ""},
+				{http.MethodPut, "/matches/%[4]d/score", `{"player1_score": 3, "player2_score": 0}`},
+				{http.MethodPost, "/matches/%[4]d/finish", ""},
+				{http.MethodGet, "/seasons/%[5]d", ""},
+				{http.MethodGet, "/seasons/%[5]d/standings", ""},
+				{http.MethodPut, "/seasons/%[5]d", `{"name": "Taken"}`},
+				{http.MethodDelete, "/seasons/%[5]d/championships/%[1]d", ""},
+				{http.MethodDelete, "/seasons/%[5]d", ""},
+			}
+			for _, r := range requests {
+				path := prefix + fmt.Sprintf(r.path, b.championship.ID, b.players[0].ID, b.players[1].ID, b.match.ID, b.season.ID)
+				if response := serve(router, a.token, a.organization.ID, r.method, path, r.body); response.Code != http.StatusNotFound {
+					t.Errorf("%s %s: got %d, want 404: %s", r.method, path, response.Code, response.Body)
+				}
+			}
+		})
+
+		t.Run(fmt.Sprintf("v%d references", version), func(t *testing.T) {
+			// Records of the own organization cannot be tied to records of
+			// another one
+			requests := []struct{ method, path, body string }{
+				{http.MethodPost, "/players", fmt.Sprintf(`{"name": "Intruder", "championship_ids": [%d]}`, b.championship.ID)},
+				{http.MethodPut, fmt.Sprintf("/players/%d", a.players[0].ID), fmt.Sprintf(`{"championship_ids": [%d, %d]}`, a.championship.ID, b.championship.ID)},
+				{http.MethodPost, "/matches", fmt.Sprintf(`{"championship_id": %d, "player1": %q, "player2": %q, "player1_id": %d, "player2_id": %d}`,
+					b.championship.ID, b.players[0].Name, b.players[1].Name, b.players[0].ID, b.players[1].ID)},
+				{http.MethodPut, fmt.Sprintf("/seasons/%d/championships/%d", a.season.ID, b.championship.ID), ""},
+				{http.MethodGet, fmt.Sprintf("/players/%d/vs/%d", a.players[0].ID, b.players[0].ID), ""},
+			}
+			for _, r := range requests {
+				path := prefix + r.path
+				if response := serve(router, a.token, a.organization.ID, r.method, path, r.body); response.Code < 400 || response.Code >= 500 {
+					t.Errorf("%s %s: got %d, want a client error: %s", r.method, path, response.Code, response.Body)
+				}
+			}
+		})
+
+		t.Run(fmt.Sprintf("v%d lists", version), func(t *testing.T) {
+			for _, path := range []string{"/championships", "/players", "/matches", "/seasons"} {
+				response := serve(router, a.token, a.organization.ID, http.MethodGet, prefix+path, "")
+				if response.Code != http.StatusOK {
+					t.Errorf("GET %s: got %d, want 200: %s", path, response.Code, response.Body)
+					continue
+				}
+				if !strings.Contains(response.Body.String(), "Alpha") {
+					t.Errorf("GET %s: records of the organization missing: %s", path, response.Body)
+				}
+				if strings.Contains(response.Body.String(), "Beta") {
+					t.Errorf("GET %s: records of another organization listed: %s", path, response.Body)
+				}
+			}
+		})
+
+		t.Run(fmt.Sprintf("v%d other organization", version), func(t *testing.T) {
+			// Naming the other organization does not help without membership
+			path := prefix + fmt.Sprintf("/championships/%d", b.championship.ID)
+			if response := serve(router, a.token, b.organization.ID, http.MethodGet, path, ""); response.Code != http.StatusForbidden {
+				t.Errorf("GET %s: got %d, want 403: %s", path, response.Code, response.Body)
+			}
+		})
+	}
+
+	t.Run("api key", func(t *testing.T) {
+		key, keyPrefix, hash, err := auth.NewAPIKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Create(&models.APIKey{
+			ChampionshipID:  a.championship.ID,
+			Name:            "Scores",
+			Prefix:          keyPrefix,
+			KeyHash:         hash,
+			Scope:           models.APIKeyScopeScoreEntry,
+			CreatedByUserID: a.owner.ID,
+		}).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		prefix := openapi.Prefix(openapi.Latest)
+		for _, r := range []struct{ method, path, body string }{
+			{http.MethodGet, fmt.Sprintf("/championships/%d", b.championship.ID), ""},
+			{http.MethodGet, fmt.Sprintf("/matches/%d", b.match.ID), ""},
+			{http.MethodPut, fmt.Sprintf("/matches/%d/score", b.match.ID), `{"player1_score": 3, "player2_score": 0}`},
+		} {
+			path := prefix + r.path
+			if response := serve(router, key, b.organization.ID, r.method, path, r.body); response.Code < 400 || response.Code >= 500 {
+				t.Errorf("%s %s: got %d, want a client error: %s", r.method, path, response.Code, response.Body)
+			}
+		}
+	})
+
+	// Nothing of the other organization changed
+	var championship models.Championship
+	if err := db.Preload("Players").First(&championship, b.championship.ID).Error; err != nil {
+		t.Fatalf("championship of the other organization: %v", err)
+	}
+	if championship.Name != b.championship.Name || championship.Status != b.championship.Status || championship.SeasonID == nil || len(championship.Players) != 2 {
+		t.Errorf("championship of the other organization changed: %+v", championship)
+	}
+	for _, player := range b.players {
+		var stored models.Player
+		if err := db.First(&stored, player.ID).Error; err != nil || stored.Name != player.Name {
+			t.Errorf("player of the other organization changed: %+v, %v", stored, err)
+		}
+	}
+	var matches []models.Match
+	if err := db.Where("championship_id = ?", b.championship.ID).Find(&matches).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].Status != models.MatchStatusPending || matches[0].Player1Score != 0 {
+		t.Errorf("matches of the other organization changed: %+v", matches)
+	}
+	var season models.Season
+	if err := db.First(&season, b.season.ID).Error; err != nil || season.Name != b.season.Name {
+		t.Errorf("season of the other organization changed: %+v, %v", season, err)
+	}
+}
@@ -0,0 +1,89 @@
+package access
+
+import (
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationRole resolves the role a user has in an organization. Only
+// membership counts, the admin flag of a user grants no access to
+// organizations. An empty role means the user is no member.
+func OrganizationRole(db *gorm.DB, userID uint, organizationID uint) (models.OrganizationRole, error) {
+	var member models.OrganizationMember
+	if err := db.Where("organization_id = ? AND user_id = ?", organizationID, userID).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return member.Role, nil
+}
+
+// ChampionshipRole resolves the role a user has in a championship. Admins of
+// the championship's organization act as owners, and a user linked to a player
+// of the championship is at least a player. Users outside the organization
+// never have access. An empty role means the user has no access.
+func ChampionshipRole(db *gorm.DB, userID uint, championshipID uint) (models.ChampionshipRole, error) {
+	var championship models.Championship
+	if err := db.Select("id", "organization_id").First(&championship, championshipID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	organizationRole, err := OrganizationRole(db, userID, championship.OrganizationID)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case organizationRole == "":
+		return "", nil
+	case organizationRole.AtLeast(models.OrganizationRoleAdmin):
+		return models.ChampionshipRoleOwner, nil
+	}
+
+	var member models.ChampionshipMember
+	err = db.Where("championship_id = ? AND user_id = ?", championshipID, userID).First(&member).Error
+	if err == nil {
+		return member.Role, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return "", err
+	}
+
+	var user models.User
+	if err := db.First(&user, userID).Error; err != nil {
+		return "", err
+	}
+
+	if user.PlayerID != nil {
+		var count int64
+		if err := db.Table("player_championships").
+			Where("player_id = ? AND championship_id = ?", *user.PlayerID, championshipID).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			return models.ChampionshipRolePlayer, nil
+		}
+	}
+
+	// Every organization member may look at its championships
+	return models.ChampionshipRoleViewer, nil
+}
+
+// LinkedPlayer returns the player a user is linked to, or nil if there is none
+func LinkedPlayer(db *gorm.DB, userID uint) (*models.Player, error) {
+	var user models.User
+	if err := db.Preload("Player").First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return user.Player, nil
+}
@@ -0,0 +1,414 @@
+package achievements
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/projections"
+	"scoretracker/backend/internal/standings"
+	"scoretracker/backend/internal/stats"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PlayerHistory is what a player has achieved so far, including the match
+// being evaluated
+type PlayerHistory struct {
+	Played    int
+	Wins      int
+	WinStreak int
+}
+
+// MatchEvent is a finished match seen from one of its players
+type MatchEvent struct {
+	Match   models.Match
+	Player  string
+	Outcome stats.Outcome
+	History PlayerHistory
+	// Player of the organization rated highest by projections.Rate before the
+	// match, empty if nobody was clearly ahead
+	TopRatedBefore string
+}
+
+// ChampionshipEvent is a completed championship seen from one of its participants
+type ChampionshipEvent struct {
+	Completion standings.Completion
+	Player     string
+}
+
+// Rule awards a badge when one of its conditions holds
+type Rule struct {
+	Badge          models.Badge
+	Description    string
+	OnMatch        func(event MatchEvent) bool
+	OnChampionship func(event ChampionshipEvent) bool
+}
+
+var Rules = []Rule{
+	{
+		Badge:       models.BadgeFirstWin,
+		Description: "Won a match for the first time",
+		OnMatch: func(event MatchEvent) bool {
+			return event.Outcome == stats.OutcomeWin && event.History.Wins == 1
+		},
+	},
+	{
+		Badge:       models.BadgeWinStreak10,
+		Description: "Won 10 matches in a row",
+		OnMatch: func(event MatchEvent) bool {
+			return event.History.WinStreak >= 10
+		},
+	},
+	{
+		Badge:       models.BadgeGiantKiller,
+		Description: "Beat the top-rated player of the organization",
+		OnMatch: func(event MatchEvent) bool {
+			return event.Outcome == stats.OutcomeWin && event.TopRatedBefore != "" &&
+				event.TopRatedBefore == stats.Opponent(event.Match, event.Player)
+		},
+	},
+	{
+		Badge:       models.BadgePerfectRoundRobin,
+		Description: "Won every match against every other participant of a championship",
+		OnChampionship: func(event ChampionshipEvent) bool {
+			opponents := make(map[string]bool)
+			for _, match := range event.Completion.Matches {
+				if match.Player1 != event.Player && match.Player2 != event.Player {
+					continue
+				}
+				if stats.OutcomeFor(match, event.Player) != stats.OutcomeWin {
+					return false
+				}
+				opponents[stats.Opponent(match, event.Player)] = true
+			}
+			return len(opponents) > 0 && len(opponents) == len(event.Completion.Ranking)-1
+		},
+	},
+}
+
+// Award is a badge earned by a player at a match
+type Award struct {
+	Player         string
+	Badge          models.Badge
+	MatchID        uint
+	ChampionshipID uint
+	AwardedAt      time.Time
+}
+
+// Evaluate replays the history of an organization and returns the first award
+// of every badge for every player. Matches must be ordered by finished_at and
+// may include unfinished ones.
+func Evaluate(championships []models.Championship, matches []models.Match) []Award {
+	var awards []Award
+	awarded := make(map[string]map[models.Badge]bool)
+	award := func(player string, badges []models.Badge, match models.Match) {
+		if awarded[player] == nil {
+			awarded[player] = make(map[models.Badge]bool)
+		}
+		for _, badge := range badges {
+			if !awarded[player][badge] {
+				awarded[player][badge] = true
+				awards = append(awards, newAward(player, badge, match))
+			}
+		}
+	}
+
+	histories := make(map[string]*PlayerHistory)
+	ratings := make(map[string]stats.Record)
+
+	for _, match := range matches {
+		if match.Status != models.MatchStatusFinished {
+			continue
+		}
+
+		topRated := topRatedOf(ratings)
+		for _, player := range []string{match.Player1, match.Player2} {
+			if histories[player] == nil {
+				histories[player] = &PlayerHistory{}
+			}
+			history := histories[player]
+
+			outcome := stats.OutcomeFor(match, player)
+			history.add(outcome)
+			ratings[player] = rate(ratings[player], outcome)
+
+			event := MatchEvent{Match: match, Player: player, Outcome: outcome, History: *history, TopRatedBefore: topRated}
+			award(player, matchBadges(event), match)
+		}
+	}
+
+	for _, completion := range standings.Completed(championships, matches) {
+		for _, player := range completion.Ranking {
+			award(player, championshipBadges(ChampionshipEvent{Completion: completion, Player: player}), completion.LastMatch)
+		}
+	}
+
+	return awards
+}
+
+func (h *PlayerHistory) add(outcome stats.Outcome) {
+	h.Played++
+	if outcome == stats.OutcomeWin {
+		h.Wins++
+		h.WinStreak++
+	} else {
+		h.WinStreak = 0
+	}
+}
+
+// matchBadges returns the badges whose rules hold for a finished match
+func matchBadges(event MatchEvent) []models.Badge {
+	var badges []models.Badge
+	for _, rule := range Rules {
+		if rule.OnMatch != nil && rule.OnMatch(event) {
+			badges = append(badges, rule.Badge)
+		}
+	}
+	return badges
+}
+
+// championshipBadges returns the badges whose rules hold for a completed championship
+func championshipBadges(event ChampionshipEvent) []models.Badge {
+	var badges []models.Badge
+	for _, rule := range Rules {
+		if rule.OnChampionship != nil && rule.OnChampionship(event) {
+			badges = append(badges, rule.Badge)
+		}
+	}
+	return badges
+}
+
+func newAward(player string, badge models.Badge, match models.Match) Award {
+	awardedAt := match.UpdatedAt
+	if match.FinishedAt != nil {
+		awardedAt = *match.FinishedAt
+	}
+	return Award{
+		Player:         player,
+		Badge:          badge,
+		MatchID:        match.ID,
+		ChampionshipID: match.ChampionshipID,
+		AwardedAt:      awardedAt,
+	}
+}
+
+// rate adds the outcome of a match to a player's record
+func rate(record stats.Record, outcome stats.Outcome) stats.Record {
+	record.Played++
+	switch outcome {
+	case stats.OutcomeWin:
+		record.Wins++
+	case stats.OutcomeDraw:
+		record.Draws++
+	default:
+		record.Losses++
+	}
+	return record
+}
+
+// topRatedOf returns the player with the highest win rate, or an empty string
+// if nobody has played or the top rating is shared
+func topRatedOf(records map[string]stats.Record) string {
+	top, best, shared := "", 0.0, false
+	for player, record := range records {
+		if record.Played == 0 {
+			continue
+		}
+		rating := projections.Rate(record).WinRate
+		switch {
+		case rating > best:
+			top, best, shared = player, rating, false
+		case rating == best:
+			shared = true
+		}
+	}
+	if shared {
+		return ""
+	}
+	return top
+}
+
+// AwardBadges evaluates the history of an organization and stores badges the
+// players do not have yet. Badges are never taken away. Returns the number of
+// new badges.
+func AwardBadges(db *gorm.DB, organizationID uint) (int, error) {
+	var championships []models.Championship
+	if err := db.Preload("Players").Where("organization_id = ?", organizationID).Find(&championships).Error; err != nil {
+		return 0, err
+	}
+
+	// Matches of deleted championships do not count
+	var championshipIDs []uint
+	for _, championship := range championships {
+		championshipIDs = append(championshipIDs, championship.ID)
+	}
+	if len(championshipIDs) == 0 {
+		return 0, nil
+	}
+
+	var matches []models.Match
+	if err := db.Where("organization_id = ? AND championship_id IN ?", organizationID, championshipIDs).
+		Order("finished_at ASC, id ASC").Find(&matches).Error; err != nil {
+		return 0, err
+	}
+
+	return store(db, organizationID, Evaluate(championships, matches))
+}
+
+// AwardMatch stores the badges the players earned with a newly finished
+// match, including those for completing its championship. It reads the
+// history of the match's players and championship and the ratings before the
+// match, and can be repeated. Returns the number of new badges.
+func AwardMatch(db *gorm.DB, match models.Match) (int, error) {
+	if match.Status != models.MatchStatusFinished || match.FinishedAt == nil {
+		return 0, nil
+	}
+
+	var championship models.Championship
+	if err := db.Preload("Players").Where("organization_id = ?", match.OrganizationID).
+		First(&championship, match.ChampionshipID).Error; err != nil {
+		// Matches of deleted championships do not count
+		if err == gorm.ErrRecordNotFound {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	ratings, err := ratingsBefore(db, match)
+	if err != nil {
+		return 0, err
+	}
+	topRated := topRatedOf(ratings)
+
+	var awards []Award
+	for _, player := range []string{match.Player1, match.Player2} {
+		var results []models.Match
+		if err := finishedResults(db, match).Where("player1 = ? OR player2 = ?", player, player).
+			Where("finished_at < ? OR (finished_at = ? AND id <= ?)", match.FinishedAt, match.FinishedAt, match.ID).
+			Order("finished_at ASC, id ASC").Find(&results).Error; err != nil {
+			return 0, err
+		}
+
+		var history PlayerHistory
+		for _, result := range results {
+			history.add(stats.OutcomeFor(result, player))
+		}
+
+		event := MatchEvent{Match: match, Player: player, Outcome: stats.OutcomeFor(match, player), History: history, TopRatedBefore: topRated}
+		for _, badge := range matchBadges(event) {
+			awards = append(awards, newAward(player, badge, match))
+		}
+	}
+
+	// The match may complete its championship
+	var matches []models.Match
+	if err := db.Where("championship_id = ?", championship.ID).Find(&matches).Error; err != nil {
+		return 0, err
+	}
+	if completed := standings.Completed([]models.Championship{championship}, matches); len(completed) > 0 && completed[0].LastMatch.ID == match.ID {
+		for _, player := range completed[0].Ranking {
+			for _, badge := range championshipBadges(ChampionshipEvent{Completion: completed[0], Player: player}) {
+				awards = append(awards, newAward(player, badge, match))
+			}
+		}
+	}
+
+	return store(db, match.OrganizationID, awards)
+}
+
+// finishedResults selects the finished matches of the match's organization in
+// championships that were not deleted
+func finishedResults(db *gorm.DB, match models.Match) *gorm.DB {
+	championships := db.Session(&gorm.Session{NewDB: true}).Model(&models.Championship{}).
+		Select("id").Where("organization_id = ?", match.OrganizationID)
+	return db.Where("organization_id = ? AND status = ?", match.OrganizationID, models.MatchStatusFinished).
+		Where("championship_id IN (?)", championships)
+}
+
+// ratingsBefore sums up the results of every player of the organization
+// finished before the match
+func ratingsBefore(db *gorm.DB, match models.Match) (map[string]stats.Record, error) {
+	ratings := make(map[string]stats.Record)
+	for _, column := range []string{"player1", "player2"} {
+		var rows []struct {
+			Player string
+			Played int
+			Wins   int
+			Draws  int
+		}
+		if err := finishedResults(db.Model(&models.Match{}), match).
+			Where("finished_at < ? OR (finished_at = ? AND id < ?)", match.FinishedAt, match.FinishedAt, match.ID).
+			Select(column + " AS player, COUNT(*) AS played, " +
+				"SUM(CASE WHEN winner = " + column + " THEN 1 ELSE 0 END) AS wins, " +
+				"SUM(CASE WHEN winner IS NULL THEN 1 ELSE 0 END) AS draws").
+			Group(column).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			record := ratings[row.Player]
+			record.Played += row.Played
+			record.Wins += row.Wins
+			record.Draws += row.Draws
+			record.Losses += row.Played - row.Wins - row.Draws
+			ratings[row.Player] = record
+		}
+	}
+	return ratings, nil
+}
+
+// store saves the awards of players that still exist. Badges players already
+// have are kept. Returns the number of new badges.
+func store(db *gorm.DB, organizationID uint, awards []Award) (int, error) {
+	if len(awards) == 0 {
+		return 0, nil
+	}
+
+	var players []models.Player
+	if err := db.Where("organization_id = ?", organizationID).Find(&players).Error; err != nil {
+		return 0, err
+	}
+	playerIDs := make(map[string]uint, len(players))
+	for _, player := range players {
+		playerIDs[player.Name] = player.ID
+	}
+
+	var achievements []models.Achievement
+	for _, award := range awards {
+		playerID, ok := playerIDs[award.Player]
+		if !ok {
+			continue
+		}
+		achievements = append(achievements, models.Achievement{
+			OrganizationID: organizationID,
+			PlayerID:       playerID,
+			Badge:          award.Badge,
+			MatchID:        award.MatchID,
+			ChampionshipID: award.ChampionshipID,
+			AwardedAt:      award.AwardedAt,
+		})
+	}
+	if len(achievements) == 0 {
+		return 0, nil
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&achievements)
+	return int(result.RowsAffected), result.Error
+}
+
+// Describe returns the description of a badge
+func Describe(badge models.Badge) string {
+	for _, rule := range Rules {
+		if rule.Badge == badge {
+			return rule.Description
+		}
+	}
+	return ""
+}
+
+// Earned is an achievement together with the description of its badge
+type Earned struct {
+	models.Achievement
+	Description string `json:"description"`
+}
@@ -0,0 +1,146 @@
+// Package apiv2 holds the representations of version 2 of the API where they
+// differ from version 1. Matches refer to players by ID as well as by name,
+// and standings include the results behind the points. The handlers build
+// the version 1 values and convert them with the functions of this package.
+package apiv2
+
+import (
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/service"
+	"scoretracker/backend/internal/standings"
+)
+
+// PlayerRef refers to a player of the organization. The ID is null if no
+// player has the name, e.g. because the player was renamed after the match.
+type PlayerRef struct {
+	ID   *uint  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Players looks up the player of an organization with the given name
+type Players func(organizationID uint, name string) PlayerRef
+
+type Match struct {
+	models.Match
+	Player1    PlayerRef  `json:"player1"`
+	Player2    PlayerRef  `json:"player2"`
+	Winner     *PlayerRef `json:"winner"`
+	ReportedBy *PlayerRef `json:"reported_by"`
+}
+
+func NewMatch(match models.Match, players Players) Match {
+	result := Match{
+		Match:   match,
+		Player1: players(match.OrganizationID, match.Player1),
+		Player2: players(match.OrganizationID, match.Player2),
+	}
+	if match.Winner != nil {
+		winner := players(match.OrganizationID, *match.Winner)
+		result.Winner = &winner
+	}
+	if match.ReportedBy != nil {
+		reporter := players(match.OrganizationID, *match.ReportedBy)
+		result.ReportedBy = &reporter
+	}
+	return result
+}
+
+func NewMatches(matches []models.Match, players Players) []Match {
+	result := make([]Match, 0, len(matches))
+	for _, match := range matches {
+		result = append(result, NewMatch(match, players))
+	}
+	return result
+}
+
+type Championship struct {
+	models.Championship
+	Matches []Match `json:"matches,omitempty"`
+}
+
+func NewChampionship(championship models.Championship, players Players) Championship {
+	result := Championship{Championship: championship}
+	if len(championship.Matches) > 0 {
+		result.Matches = NewMatches(championship.Matches, players)
+	}
+	return result
+}
+
+type Draw struct {
+	service.Draw
+	Matches []Match `json:"matches"`
+}
+
+func NewDraw(draw service.Draw, players Players) Draw {
+	return Draw{Draw: draw, Matches: NewMatches(draw.Matches, players)}
+}
+
+type Record struct {
+	models.Record
+	Match *Match `json:"match,omitempty"`
+}
+
+func NewRecord(record models.Record, players Players) Record {
+	result := Record{Record: record}
+	if record.Match != nil {
+		match := NewMatch(*record.Match, players)
+		result.Match = &match
+	}
+	return result
+}
+
+type Achievement struct {
+	achievements.Earned
+	Match *Match `json:"match,omitempty"`
+}
+
+func NewAchievement(achievement achievements.Earned, players Players) Achievement {
+	result := Achievement{Earned: achievement}
+	if achievement.Match != nil {
+		match := NewMatch(*achievement.Match, players)
+		result.Match = &match
+	}
+	return result
+}
+
+// Standing replaces the player name of version 1 with a reference and adds
+// the results of the player
+type Standing struct {
+	Position     int       `json:"position"`
+	Player       PlayerRef `json:"player"`
+	Points       int       `json:"points"`
+	Played       int       `json:"played"`
+	Won          int       `json:"won"`
+	Drawn        int       `json:"drawn"`
+	Lost         int       `json:"lost"`
+	ScoreFor     int       `json:"score_for"`
+	ScoreAgainst int       `json:"score_against"`
+}
+
+func NewStanding(row standings.Row, organizationID uint, players Players) Standing {
+	return Standing{
+		Position:     row.Position,
+		Player:       players(organizationID, row.PlayerName),
+		Points:       row.Points,
+		Played:       row.Played,
+		Won:          row.Won,
+		Drawn:        row.Drawn,
+		Lost:         row.Lost,
+		ScoreFor:     row.ScoreFor,
+		ScoreAgainst: row.ScoreAgainst,
+	}
+}
+
+type StandingsSnapshot struct {
+	service.StandingsSnapshot
+	Standings []Standing `json:"standings"`
+}
+
+func NewStandingsSnapshot(snapshot service.StandingsSnapshot, organizationID uint, players Players) StandingsSnapshot {
+	result := StandingsSnapshot{StandingsSnapshot: snapshot, Standings: make([]Standing, 0, len(snapshot.Rows))}
+	for _, row := range snapshot.Rows {
+		result.Standings = append(result.Standings, NewStanding(row, organizationID, players))
+	}
+	return result
+}
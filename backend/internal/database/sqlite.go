@@ -0,0 +1,61 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// connectSQLite opens the embedded database at DB_PATH. With ":memory:" the
+// database lives in memory and is lost when the process exits.
+func connectSQLite() (*gorm.DB, error) {
+	path := getEnv("DB_PATH", "scoretracker.db")
+
+	dsn := path
+	if path == ":memory:" {
+		// Named and shared so every connection sees the same database
+		dsn = "file:scoretracker?mode=memory&cache=shared"
+	}
+
+	fmt.Printf("Using SQLite database: %s\n", path)
+
+	db, err := OpenSQLite(dsn, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Successfully connected to database\n")
+	return db, nil
+}
+
+// OpenSQLite opens the SQLite database of a DSN, either a path or a URI such
+// as "file:name?mode=memory&cache=shared" for a named in-memory database
+func OpenSQLite(dsn string, config *gorm.Config) (*gorm.DB, error) {
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	dsn += separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	db, err := gorm.Open(sqlite.Open(dsn), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer; one connection avoids lock errors and
+	// keeps an in-memory database alive for the lifetime of the process
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	return db, nil
+}
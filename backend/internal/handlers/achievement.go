@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBadges lists every badge that can be earned
+func (h *PlayerHandler) GetBadges(c *gin.Context) {
+	type Badge struct {
+		Badge       models.Badge `json:"badge"`
+		Description string       `json:"description"`
+	}
+
+	badges := make([]Badge, 0, len(achievements.Rules))
+	for _, rule := range achievements.Rules {
+		badges = append(badges, Badge{Badge: rule.Badge, Description: rule.Description})
+	}
+
+	c.JSON(http.StatusOK, badges)
+}
+
+func (h *PlayerHandler) GetAchievements(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	earned, err := h.Players.Achievements(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, h.DB, http.StatusOK, earned)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/requests"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *ChampionshipHandler) GetAPIKeys(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	keys, err := h.APIKeys.List(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *ChampionshipHandler) CreateAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	var request requests.CreateAPIKey
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	userID, _ := middleware.CurrentUserID(c)
+	apiKey, key, err := h.APIKeys.Create(middleware.CurrentOrganizationID(c), uint(id), models.APIKey{
+		Name:            request.Name,
+		Scope:           request.Scope,
+		CreatedByUserID: userID,
+		ExpiresAt:       request.ExpiresAt,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// The plain key is only returned once and cannot be recovered later
+	c.JSON(http.StatusCreated, gin.H{"key": key, "api_key": apiKey})
+}
+
+func (h *ChampionshipHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	keyID, err := strconv.ParseUint(c.Param("keyId"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid API key ID")
+		return
+	}
+
+	apiKey, err := h.APIKeys.Revoke(middleware.CurrentOrganizationID(c), uint(id), uint(keyID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, apiKey)
+}
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"scoretracker/backend/internal/access"
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/requests"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AuthHandler struct {
+	DB     *gorm.DB
+	Tokens *auth.TokenService
+}
+
+func NewAuthHandler(db *gorm.DB, tokens *auth.TokenService) *AuthHandler {
+	return &AuthHandler{DB: db, Tokens: tokens}
+}
+
+func (h *AuthHandler) Register(c *gin.Context) {
+	var request requests.Register
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	email := normalizeEmail(request.Email)
+
+	var existingCount int64
+	if err := h.DB.Model(&models.User{}).Where("email = ?", email).Count(&existingCount).Error; err != nil {
+		problem.Internal(c, "Failed to check email", err)
+		return
+	}
+	if existingCount > 0 {
+		problem.Abort(c, http.StatusConflict, problem.CodeEmailTaken, "Email is already registered")
+		return
+	}
+
+	hash, err := auth.HashPassword(request.Password)
+	if err != nil {
+		problem.Internal(c, "Failed to create user", err)
+		return
+	}
+
+	// New users create their own organization. Owners of organizations
+	// without one, such as the default organization of data from before
+	// organizations, are set up with the grant-owner command.
+	user := models.User{
+		Email:        email,
+		PasswordHash: hash,
+	}
+
+	if err := h.DB.Create(&user).Error; err != nil {
+		problem.Internal(c, "Failed to create user", err)
+		return
+	}
+
+	h.DB.Preload("Player").First(&user, user.ID)
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var request requests.Login
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	var user models.User
+	if err := h.DB.Where("email = ?", normalizeEmail(request.Email)).First(&user).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidCredentials, "Invalid email or password")
+			return
+		}
+		problem.Internal(c, "Failed to fetch user", err)
+		return
+	}
+
+	if !auth.CheckPassword(user.PasswordHash, request.Password) {
+		problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidCredentials, "Invalid email or password")
+		return
+	}
+
+	tokens, err := h.issueTokens(h.DB, user)
+	if err != nil {
+		problem.Internal(c, "Failed to issue tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var request requests.RefreshToken
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	var tokens gin.H
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var stored models.RefreshToken
+		if err := tx.Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?",
+			auth.HashToken(request.RefreshToken), time.Now()).First(&stored).Error; err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			return err
+		}
+
+		// Rotate: the presented refresh token can only be used once
+		if err := tx.Model(&stored).Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+
+		issued, err := h.issueTokens(tx, user)
+		if err != nil {
+			return err
+		}
+		tokens = issued
+		return nil
+	})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidRefreshToken, "Invalid or expired refresh token")
+			return
+		}
+		problem.Internal(c, "Failed to refresh tokens", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var request requests.RefreshToken
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	if err := h.DB.Model(&models.RefreshToken{}).
+		Where("token_hash = ? AND revoked_at IS NULL", auth.HashToken(request.RefreshToken)).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		problem.Internal(c, "Failed to log out", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *AuthHandler) GetCurrentUser(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *AuthHandler) UpdateCurrentUser(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	var request requests.UpdateUser
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	if !request.PlayerIDSet {
+		c.JSON(http.StatusOK, user)
+		return
+	}
+	if request.PlayerID != nil {
+		if !h.checkPlayerLink(c, *request.PlayerID, user.ID) {
+			return
+		}
+	}
+
+	// Not through the user, whose preloaded player would be saved back
+	if err := h.DB.Model(&models.User{}).Where("id = ?", user.ID).Update("player_id", request.PlayerID).Error; err != nil {
+		problem.Internal(c, "Failed to update user", err)
+		return
+	}
+
+	var updated models.User
+	if err := h.DB.Preload("Player").First(&updated, user.ID).Error; err != nil {
+		problem.Internal(c, "Failed to reload user", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *AuthHandler) GetMyMatches(c *gin.Context) {
+	user, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.Player == nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodePlayerNotLinked, "User is not linked to a player")
+		return
+	}
+
+	var matches []models.Match
+	if err := h.DB.Where("organization_id = ? AND (player1 = ? OR player2 = ?)", user.Player.OrganizationID, user.Player.Name, user.Player.Name).
+		Preload("Championship").Order("created_at DESC").Find(&matches).Error; err != nil {
+		problem.Internal(c, "Failed to fetch matches", err)
+		return
+	}
+
+	respond(c, h.DB, http.StatusOK, matches)
+}
+
+// currentUser loads the authenticated user and writes an error response if that fails
+func (h *AuthHandler) currentUser(c *gin.Context) (models.User, bool) {
+	var user models.User
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		problem.Abort(c, http.StatusUnauthorized, problem.CodeAuthenticationRequired, "Authentication required")
+		return user, false
+	}
+
+	if err := h.DB.Preload("Player").First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidToken, "User no longer exists")
+			return user, false
+		}
+		problem.Internal(c, "Failed to fetch user", err)
+		return user, false
+	}
+
+	return user, true
+}
+
+// checkPlayerLink verifies that a player exists in an organization the user
+// administers and is not linked to another user, and writes an error response
+// if it is not. Members are linked by the admins, see PlayerHandler.LinkUser.
+func (h *AuthHandler) checkPlayerLink(c *gin.Context, playerID uint, userID uint) bool {
+	var player models.Player
+	if err := h.DB.First(&player, playerID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			problem.Invalid(c, problem.FieldError{Field: "player_id", Code: problem.FieldNotFound, Message: "Player not found"})
+			return false
+		}
+		problem.Internal(c, "Failed to verify player", err)
+		return false
+	}
+
+	role, err := access.OrganizationRole(h.DB, userID, player.OrganizationID)
+	if err != nil {
+		problem.Internal(c, "Failed to verify player", err)
+		return false
+	}
+	if role == "" {
+		problem.Abort(c, http.StatusForbidden, problem.CodeNotOrganizationMember, "Player belongs to an organization you are not a member of")
+		return false
+	}
+	if !role.AtLeast(models.OrganizationRoleAdmin) {
+		problem.Abort(c, http.StatusForbidden, problem.CodeForbidden, "Only organization admins can link users to players")
+		return false
+	}
+
+	var linkedCount int64
+	if err := h.DB.Model(&models.User{}).Where("player_id = ? AND id <> ?", playerID, userID).Count(&linkedCount).Error; err != nil {
+		problem.Internal(c, "Failed to verify player", err)
+		return false
+	}
+	if linkedCount > 0 {
+		problem.Abort(c, http.StatusConflict, problem.CodePlayerAlreadyLinked, "Player is already linked to another user")
+		return false
+	}
+
+	return true
+}
+
+func (h *AuthHandler) issueTokens(db *gorm.DB, user models.User) (gin.H, error) {
+	accessToken, expiresAt, err := h.Tokens.IssueAccessToken(user.ID, user.PlayerID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	stored := models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: refreshHash,
+		ExpiresAt: time.Now().Add(auth.RefreshTokenTTL),
+	}
+	if err := db.Create(&stored).Error; err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"access_token":       accessToken,
+		"token_type":         "Bearer",
+		"expires_at":         expiresAt,
+		"refresh_token":      refreshToken,
+		"refresh_expires_at": stored.ExpiresAt,
+	}, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/requests"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *PlayerHandler) GetUnavailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	unavailability, err := h.Players.Unavailability(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, unavailability)
+}
+
+func (h *PlayerHandler) CreateUnavailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	var request requests.CreateUnavailability
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	unavailability, err := h.Players.AddUnavailability(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), models.PlayerUnavailability{
+		Kind:      request.Kind,
+		Reason:    request.Reason,
+		Weekday:   request.Weekday,
+		StartDate: request.StartDate,
+		EndDate:   request.EndDate,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, unavailability)
+}
+
+func (h *PlayerHandler) DeleteUnavailability(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	unavailabilityID, err := strconv.ParseUint(c.Param("unavailabilityId"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid unavailability ID")
+		return
+	}
+
+	if err := h.Players.RemoveUnavailability(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), uint(unavailabilityID)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Unavailability deleted successfully"})
+}
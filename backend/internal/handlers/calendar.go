@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"scoretracker/backend/internal/ical"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+const calendarContentType = "text/calendar; charset=utf-8"
+
+// GetPlayerCalendar lists the scheduled matches of a player. API keys get the
+// matches of their championship only.
+func (h *PlayerHandler) GetPlayerCalendar(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	// API keys only read their own championship
+	var championshipID *uint
+	if key, ok := middleware.CurrentAPIKey(c); ok {
+		championshipID = &key.ChampionshipID
+	}
+
+	player, matches, err := h.Players.Calendar(middleware.CurrentOrganizationID(c), uint(id), championshipID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"player-%d.ics\"", player.ID))
+	c.Data(http.StatusOK, calendarContentType, ical.Calendar(player.Name, matches, time.Now()))
+}
+
+func (h *ChampionshipHandler) GetChampionshipCalendar(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	championship, matches, err := h.Championships.Calendar(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"championship-%d.ics\"", championship.ID))
+	c.Data(http.StatusOK, calendarContentType, ical.Calendar(championship.Name, matches, time.Now()))
+}
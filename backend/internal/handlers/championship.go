@@ -3,122 +3,138 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
+	"scoretracker/backend/internal/middleware"
 	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/projections"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type ChampionshipHandler struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Championships service.ChampionshipService
+	Projections   service.ProjectionService
+	Members       service.ChampionshipMemberService
+	APIKeys       service.APIKeyService
 }
 
 func NewChampionshipHandler(db *gorm.DB) *ChampionshipHandler {
-	return &ChampionshipHandler{DB: db}
+	store := repository.NewStore(db)
+	return &ChampionshipHandler{
+		DB:            db,
+		Championships: service.NewChampionshipService(store),
+		Projections:   service.NewProjectionService(store, projections.NewCache()),
+		Members:       service.NewChampionshipMemberService(store),
+		APIKeys:       service.NewAPIKeyService(store),
+	}
 }
 
+// GetAllChampionships lists the championships, filtered by the status,
+// season_id, from and to query parameters, where from and to bound the
+// creation date
 func (h *ChampionshipHandler) GetAllChampionships(c *gin.Context) {
-	var championships []models.Championship
+	options, ok := listOptions(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.ChampionshipFilter{Status: models.ChampionshipStatus(c.Query("status"))}
+	if filter.SeasonID, ok = queryID(c, "season_id", "Invalid season ID"); !ok {
+		return
+	}
+	if filter.From, filter.To, ok = dateRange(c); !ok {
+		return
+	}
 
-	if err := h.DB.Order("created_at DESC").Find(&championships).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championships"})
+	championships, page, err := h.Championships.List(middleware.CurrentOrganizationID(c), filter, options)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, championships)
+	setPageHeaders(c, page)
+	respond(c, h.DB, http.StatusOK, championships)
 }
 
 func (h *ChampionshipHandler) GetChampionship(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var championship models.Championship
-	if err := h.DB.Preload("Players").Preload("Matches").First(&championship, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Championship not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championship"})
+	championship, err := h.Championships.Get(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, championship)
+	setETag(c, championship.Version)
+	respond(c, h.DB, http.StatusOK, championship)
 }
 
 func (h *ChampionshipHandler) CreateChampionship(c *gin.Context) {
-	var championship models.Championship
-
-	if err := c.ShouldBindJSON(&championship); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request requests.CreateChampionship
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
 		return
 	}
 
-	if championship.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
-		return
-	}
-
-	if err := h.DB.Create(&championship).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create championship"})
+	userID, _ := middleware.CurrentUserID(c)
+	championship, err := h.Championships.Create(middleware.CurrentOrganizationID(c), userID, request.Championship())
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, championship)
+	setETag(c, championship.Version)
+	respond(c, h.DB, http.StatusCreated, championship)
 }
 
 func (h *ChampionshipHandler) UpdateChampionship(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var championship models.Championship
-	if err := h.DB.First(&championship, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Championship not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championship"})
+	var request requests.UpdateChampionship
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
 		return
 	}
 
-	if err := c.ShouldBindJSON(&championship); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-	if err := h.DB.Save(&championship).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update championship"})
+	championship, err := h.Championships.Update(middleware.CurrentOrganizationID(c), uint(id), version, request.Apply)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, championship)
+	setETag(c, championship.Version)
+	respond(c, h.DB, http.StatusOK, championship)
 }
 
 func (h *ChampionshipHandler) DeleteChampionship(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var championship models.Championship
-	if err := h.DB.First(&championship, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Championship not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championship"})
-		return
-	}
-
-	if err := h.DB.Delete(&championship).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete championship"})
+	if err := h.Championships.Delete(middleware.CurrentOrganizationID(c), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
@@ -128,127 +144,77 @@ func (h *ChampionshipHandler) DeleteChampionship(c *gin.Context) {
 func (h *ChampionshipHandler) FinalizeChampionship(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
-		return
-	}
-
-	var championship models.Championship
-	if err := h.DB.First(&championship, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Championship not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championship"})
-		return
-	}
-
-	if championship.Status == models.ChampionshipStatusFinalized {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Championship is already finalized"})
-		return
-	}
-
-	// Check if there are at least 2 players in this championship
-	var playerCount int64
-	if err := h.DB.Table("player_championships").Where("championship_id = ?", id).Count(&playerCount).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check players"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	if playerCount < 2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "At least 2 players are required to finalize a championship"})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-	// Update only the status field
-	if err := h.DB.Model(&championship).Update("status", models.ChampionshipStatusFinalized).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finalize championship: " + err.Error()})
-		return
-	}
-
-	// Reload the championship to return the updated version
-	if err := h.DB.First(&championship, id).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload championship"})
+	championship, err := h.Championships.Finalize(middleware.CurrentOrganizationID(c), uint(id), version)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, championship)
+	setETag(c, championship.Version)
+	respond(c, h.DB, http.StatusOK, championship)
 }
 
+// GetStandings returns the current standings, or the standings as they were
+// at the time given by the as_of query parameter
 func (h *ChampionshipHandler) GetStandings(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var championship models.Championship
-	if err := h.DB.First(&championship, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Championship not found"})
+	var asOf *time.Time
+	if value := c.Query("as_of"); value != "" {
+		at, dateOnly, err := parseDateParam(value)
+		if err != nil {
+			problem.Invalid(c, problem.FieldError{Field: "as_of", Code: problem.FieldInvalidFormat, Message: "Invalid as_of, use YYYY-MM-DD or RFC 3339"})
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championship"})
-		return
+		// A date includes the whole day
+		if dateOnly {
+			at = at.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		asOf = &at
 	}
 
-	// Get all finished matches for this championship
-	var matches []models.Match
-	if err := h.DB.Where("championship_id = ? AND status = ?", id, models.MatchStatusFinished).Find(&matches).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+	table, err := h.Championships.Standings(middleware.CurrentOrganizationID(c), uint(id), asOf)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Calculate points for each player
-	points := make(map[string]int)
-	
-	for _, match := range matches {
-		if match.Winner == nil {
-			// Draw - both players get 1 point
-			points[match.Player1] += 1
-			points[match.Player2] += 1
-		} else if *match.Winner == match.Player1 {
-			// Player1 wins - 3 points
-			points[match.Player1] += 3
-			points[match.Player2] += 0
-		} else if *match.Winner == match.Player2 {
-			// Player2 wins - 3 points
-			points[match.Player1] += 0
-			points[match.Player2] += 3
-		}
-	}
+	respond(c, h.DB, http.StatusOK, table)
+}
 
-	// Get all players in championship
-	var players []models.Player
-	if err := h.DB.Joins("JOIN player_championships ON players.id = player_championships.player_id").
-		Where("player_championships.championship_id = ?", id).
-		Find(&players).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch players"})
+// GetStandingsHistory returns a standings snapshot after every day on which
+// matches were finished, or after every single match with by=match
+func (h *ChampionshipHandler) GetStandingsHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	// Build standings
-	type Standing struct {
-		PlayerName string `json:"player_name"`
-		Points     int    `json:"points"`
-	}
-	
-	standings := make([]Standing, 0, len(players))
-	for _, player := range players {
-		standings = append(standings, Standing{
-			PlayerName: player.Name,
-			Points:     points[player.Name],
-		})
+	by := c.DefaultQuery("by", "date")
+	if by != "date" && by != "match" {
+		problem.Invalid(c, problem.FieldError{Field: "by", Code: problem.FieldInvalidValue, Message: "by must be date or match"})
+		return
 	}
 
-	// Sort by points (descending)
-	for i := 0; i < len(standings)-1; i++ {
-		for j := i + 1; j < len(standings); j++ {
-			if standings[j].Points > standings[i].Points {
-				standings[i], standings[j] = standings[j], standings[i]
-			}
-		}
+	history, err := h.Championships.StandingsHistory(middleware.CurrentOrganizationID(c), uint(id), by == "match")
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, standings)
+	respond(c, h.DB, http.StatusOK, history)
 }
-
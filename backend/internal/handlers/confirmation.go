@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/requests"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *MatchHandler) ConfirmResult(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.Matches.Confirm(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), version)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
+}
+
+func (h *MatchHandler) DisputeResult(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	var request requests.Dispute
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.Matches.Dispute(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), version, request.Reason)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
+}
+
+// ResolveDispute lets an organizer set the official score of a disputed match
+func (h *MatchHandler) ResolveDispute(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	var request requests.Score
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	match, err := h.Matches.Resolve(middleware.CurrentOrganizationID(c), uint(id), version, request.Player1Score, request.Player2Score)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
+}
+
+// GetDisputes returns the organizer queue of disputed results
+func (h *MatchHandler) GetDisputes(c *gin.Context) {
+	championshipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid championship ID")
+		return
+	}
+
+	matches, err := h.Matches.Disputes(middleware.CurrentOrganizationID(c), uint(championshipID))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, h.DB, http.StatusOK, matches)
+}
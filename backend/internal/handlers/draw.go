@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindDrawRequest reads the optional draw request body
+func bindDrawRequest(c *gin.Context) (service.DrawRequest, bool) {
+	var request service.DrawRequest
+	if c.Request.ContentLength != 0 {
+		if err := requests.Bind(c, &request); err != nil {
+			problem.Binding(c, err)
+			return request, false
+		}
+	}
+	return request, true
+}
+
+// PreviewDraw shows the seeding and matches generate-matches would create
+// with the same request, without saving anything. Random draws return their
+// seed so the previewed draw can be committed unchanged.
+func (h *MatchHandler) PreviewDraw(c *gin.Context) {
+	championshipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid championship ID")
+		return
+	}
+
+	request, ok := bindDrawRequest(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.Matches.PreviewDraw(middleware.CurrentOrganizationID(c), uint(championshipID), request)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, h.DB, http.StatusOK, result)
+}
+
+// GetDraw returns the draw stored when the championship's matches were generated
+func (h *ChampionshipHandler) GetDraw(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	championship, err := h.Championships.Draw(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"method": championship.SeedingMethod,
+		"seed":   championship.DrawSeed,
+		"seeds":  championship.Seeds,
+	})
+}
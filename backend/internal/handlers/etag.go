@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag sends the version of a record as its entity tag
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", strconv.Quote(strconv.FormatUint(uint64(version), 10)))
+}
+
+// ifMatch returns the version the If-Match header requires, or 0 if any
+// version will do. Tags that cannot name a version of the record fail the
+// precondition right away, in which case it responds and returns false.
+func ifMatch(c *gin.Context) (uint, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+
+	// Only strong tags match, weak ones (W/"1") never do
+	if tag, err := strconv.Unquote(header); err == nil {
+		if version, err := strconv.ParseUint(tag, 10, 32); err == nil && version > 0 {
+			return uint(version), true
+		}
+	}
+
+	problem.Abort(c, http.StatusPreconditionFailed, problem.CodeVersionMismatch, "If-Match does not match the current version")
+	return 0, false
+}
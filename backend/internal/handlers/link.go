@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/requests"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkUser links a member of the organization to a player, which only
+// organization admins may do
+func (h *PlayerHandler) LinkUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	var request requests.LinkUser
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	user, err := h.Players.LinkUser(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), request.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UnlinkUser removes the link between a player and the user playing as them
+func (h *PlayerHandler) UnlinkUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	if err := h.Players.UnlinkUser(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unlinked successfully"})
+}
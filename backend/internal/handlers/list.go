@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listOptions reads the limit, cursor and sort query parameters of a list.
+// Without limit a page has the default size. Version 1 clients, written when
+// lists had no pages, get the largest page instead.
+func listOptions(c *gin.Context) (repository.ListOptions, bool) {
+	options := repository.ListOptions{
+		Sort:   c.Query("sort"),
+		Cursor: c.Query("cursor"),
+		Limit:  service.DefaultPageSize,
+	}
+	if middleware.CurrentAPIVersion(c) == 1 {
+		options.Limit = service.MaxPageSize
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			problem.Invalid(c, problem.FieldError{Field: "limit", Code: problem.FieldOutOfRange, Message: fmt.Sprintf("Limit must be between 1 and %d", service.MaxPageSize)})
+			return options, false
+		}
+		options.Limit = limit
+	}
+
+	return options, true
+}
+
+// setPageHeaders sends the number of rows on all pages and, unless this is
+// the last page, a link to the next one
+func setPageHeaders(c *gin.Context, page repository.Page) {
+	c.Header("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	if page.Next == "" {
+		return
+	}
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Set("cursor", page.Next)
+	next.RawQuery = query.Encode()
+	// Added, deprecated versions also link to their successor
+	c.Writer.Header().Add("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+}
+
+// queryID reads an optional ID from the query parameter name
+func queryID(c *gin.Context, name string, message string) (*uint, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		problem.Invalid(c, problem.FieldError{Field: name, Code: problem.FieldInvalidValue, Message: message})
+		return nil, false
+	}
+	result := uint(id)
+	return &result, true
+}
+
+// dateRange reads the from and to query parameters. Dates are either
+// YYYY-MM-DD, where to includes the whole day, or RFC 3339 timestamps.
+func dateRange(c *gin.Context) (from *time.Time, to *time.Time, ok bool) {
+	if value := c.Query("from"); value != "" {
+		at, _, err := parseDateParam(value)
+		if err != nil {
+			problem.Invalid(c, problem.FieldError{Field: "from", Code: problem.FieldInvalidFormat, Message: "Invalid from date, use YYYY-MM-DD or RFC 3339"})
+			return nil, nil, false
+		}
+		from = &at
+	}
+
+	if value := c.Query("to"); value != "" {
+		at, dateOnly, err := parseDateParam(value)
+		if err != nil {
+			problem.Invalid(c, problem.FieldError{Field: "to", Code: problem.FieldInvalidFormat, Message: "Invalid to date, use YYYY-MM-DD or RFC 3339"})
+			return nil, nil, false
+		}
+		if dateOnly {
+			at = at.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		to = &at
+	}
+
+	return from, to, true
+}
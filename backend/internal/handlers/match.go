@@ -1,348 +1,276 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
-	"time"
 
+	"scoretracker/backend/internal/jobs"
+	"scoretracker/backend/internal/middleware"
 	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/scheduler"
+	"scoretracker/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type MatchHandler struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Matches service.MatchService
 }
 
 func NewMatchHandler(db *gorm.DB) *MatchHandler {
-	return &MatchHandler{DB: db}
+	return &MatchHandler{DB: db, Matches: service.NewMatchService(repository.NewStore(db), resultsChanged(db))}
 }
 
-func (h *MatchHandler) GetAllMatches(c *gin.Context) {
-	var matches []models.Match
-	championshipID := c.Query("championship_id")
-
-	query := h.DB
-	if championshipID != "" {
-		id, err := strconv.ParseUint(championshipID, 10, 32)
-		if err == nil {
-			query = query.Where("championship_id = ?", id)
-		}
+// resultsChanged updates everything derived from official results
+func resultsChanged(db *gorm.DB) func(match models.Match) {
+	return func(match models.Match) {
+		jobs.ResultFinished(db, match)
 	}
+}
 
-	if err := query.Preload("Championship").Order("created_at DESC").Find(&matches).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+// GetAllMatches lists the matches, filtered by the championship_id, status,
+// player (a name), player_id, game, from and to query parameters. from and to
+// bound the date a match was finished, else is scheduled, else was created.
+func (h *MatchHandler) GetAllMatches(c *gin.Context) {
+	options, ok := listOptions(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, matches)
-}
-
-func (h *MatchHandler) GetMatch(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+	filter := repository.MatchFilter{
+		Status: models.MatchStatus(c.Query("status")),
+		Player: c.Query("player"),
+		Game:   c.Query("game"),
+	}
+	if filter.ChampionshipID, ok = queryID(c, "championship_id", "Invalid championship ID"); !ok {
 		return
 	}
-
-	var match models.Match
-	if err := h.DB.First(&match, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch match"})
+	if filter.ChampionshipID, ok = keyChampionship(c, filter.ChampionshipID); !ok {
 		return
 	}
-
-	c.JSON(http.StatusOK, match)
-}
-
-func (h *MatchHandler) CreateMatch(c *gin.Context) {
-	var match models.Match
-
-	if err := c.ShouldBindJSON(&match); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if filter.PlayerID, ok = queryID(c, "player_id", "Invalid player ID"); !ok {
 		return
 	}
-
-	if match.ChampionshipID == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Championship ID is required"})
+	if filter.From, filter.To, ok = dateRange(c); !ok {
 		return
 	}
 
-	// Verify championship exists
-	var championship models.Championship
-	if err := h.DB.First(&championship, match.ChampionshipID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Championship not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify championship"})
+	matches, page, err := h.Matches.List(middleware.CurrentOrganizationID(c), filter, options)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Validate that players are different
-	if match.Player1 == match.Player2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Players must be different"})
+	setPageHeaders(c, page)
+	respond(c, h.DB, http.StatusOK, matches)
+}
+
+func (h *MatchHandler) GetMatch(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	// Set default status if not provided
-	if match.Status == "" {
-		match.Status = models.MatchStatusPending
+	match, err := h.Matches.Get(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
 	}
 
-	// Verify players exist and are in the championship
-	var player1, player2 models.Player
-	if err := h.DB.Where("name = ?", match.Player1).First(&player1).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Player1 not found"})
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
+}
+
+func (h *MatchHandler) CreateMatch(c *gin.Context) {
+	request, ok := h.bindCreateMatch(c)
+	if !ok {
 		return
 	}
-	if err := h.DB.Where("name = ?", match.Player2).First(&player2).Error; err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Player2 not found"})
+
+	match, err := h.Matches.Create(middleware.CurrentOrganizationID(c), actor(c, h.DB), request.Match())
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Check if both players are in the championship
-	var count1, count2 int64
-	h.DB.Table("player_championships").
-		Where("player_id = ? AND championship_id = ?", player1.ID, match.ChampionshipID).
-		Count(&count1)
-	h.DB.Table("player_championships").
-		Where("player_id = ? AND championship_id = ?", player2.ID, match.ChampionshipID).
-		Count(&count2)
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusCreated, match)
+}
 
-	if count1 == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Player1 is not in this championship"})
-		return
+// bindCreateMatch reads the body of creating a match. Version 2 refers to the
+// players by ID, which are resolved to the names matches are stored with.
+func (h *MatchHandler) bindCreateMatch(c *gin.Context) (requests.CreateMatch, bool) {
+	if middleware.CurrentAPIVersion(c) < 2 {
+		var request requests.CreateMatch
+		if err := requests.Bind(c, &request); err != nil {
+			problem.Binding(c, err)
+			return request, false
+		}
+		return request, true
 	}
-	if count2 == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Player2 is not in this championship"})
-		return
+
+	var request requests.CreateMatchV2
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return requests.CreateMatch{}, false
 	}
 
-	if err := h.DB.Create(&match).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create match"})
-		return
+	player1, player2, err := h.Matches.PlayerNames(middleware.CurrentOrganizationID(c), request.Player1ID, request.Player2ID)
+	if err != nil {
+		respondError(c, err)
+		return requests.CreateMatch{}, false
 	}
 
-	c.JSON(http.StatusCreated, match)
+	return request.CreateMatch(player1, player2), true
 }
 
 func (h *MatchHandler) DeleteMatch(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
-		return
-	}
-
-	var match models.Match
-	if err := h.DB.First(&match, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch match"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	if err := h.DB.Delete(&match).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete match"})
+	if err := h.Matches.Delete(middleware.CurrentOrganizationID(c), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Match deleted successfully"})
 }
 
+// GenerateRoundRobinMatches seeds the players as requested (see PreviewDraw)
+// and creates a match for every pair of players
 func (h *MatchHandler) GenerateRoundRobinMatches(c *gin.Context) {
 	championshipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid championship ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid championship ID")
 		return
 	}
 
-	// Verify championship exists and is finalized
-	var championship models.Championship
-	if err := h.DB.Preload("Players").First(&championship, championshipID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Championship not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch championship"})
+	request, ok := bindDrawRequest(c)
+	if !ok {
 		return
 	}
 
-	if championship.Status != models.ChampionshipStatusFinalized {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Championship must be finalized before generating matches"})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-	if len(championship.Players) < 2 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "At least 2 players are required to generate matches"})
+	result, err := h.Matches.GenerateRoundRobin(middleware.CurrentOrganizationID(c), uint(championshipID), version, request)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	// Check if matches already exist for this championship
-	var existingMatchesCount int64
-	h.DB.Model(&models.Match{}).Where("championship_id = ?", championshipID).Count(&existingMatchesCount)
-	if existingMatchesCount > 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Matches already exist for this championship"})
+	respond(c, h.DB, http.StatusCreated, gin.H{"message": "Matches generated successfully", "count": len(result.Matches), "matches": result.Matches, "draw": result})
+}
+
+func (h *MatchHandler) ScheduleMatches(c *gin.Context) {
+	championshipID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid championship ID")
 		return
 	}
 
-	// Generate round-robin matches (each player plays against every other player once)
-	playerNames := make([]string, len(championship.Players))
-	for i, player := range championship.Players {
-		playerNames[i] = player.Name
-	}
-
-	matches := make([]models.Match, 0)
-	for i := 0; i < len(playerNames); i++ {
-		for j := i + 1; j < len(playerNames); j++ {
-			match := models.Match{
-				ChampionshipID: uint(championshipID),
-				Player1:        playerNames[i],
-				Player2:        playerNames[j],
-				Game:           championship.Name, // Use championship name as game name
-				Status:         models.MatchStatusPending,
-				Player1Score:   0,
-				Player2Score:   0,
-			}
-			matches = append(matches, match)
-		}
+	var request requests.Schedule
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
 	}
 
-	// Create all matches
-	if err := h.DB.Create(&matches).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create matches: " + err.Error()})
+	matches, err := h.Matches.Schedule(middleware.CurrentOrganizationID(c), uint(championshipID), request.Slots, request.MatchesPerSlot)
+	if err != nil {
+		var conflictErr *scheduler.ConflictError
+		if errors.As(err, &conflictErr) {
+			conflicts := problem.New(http.StatusUnprocessableEntity, problem.CodeScheduleConflicts, "No valid schedule exists")
+			conflicts.Extensions = map[string]interface{}{"conflicts": conflictErr.Conflicts}
+			problem.Write(c, conflicts)
+			return
+		}
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Matches generated successfully", "count": len(matches), "matches": matches})
+	respond(c, h.DB, http.StatusOK, gin.H{"message": "Matches scheduled successfully", "count": len(matches), "matches": matches})
 }
 
 func (h *MatchHandler) StartMatch(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
-		return
-	}
-
-	var match models.Match
-	if err := h.DB.First(&match, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch match"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	if match.Status != models.MatchStatusPending {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Match is not in pending status"})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-	now := time.Now()
-	match.Status = models.MatchStatusStarted
-	match.StartedAt = &now
-
-	if err := h.DB.Save(&match).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start match"})
+	match, err := h.Matches.Start(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), version)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, match)
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
 }
 
 func (h *MatchHandler) UpdateMatchScore(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var request struct {
-		Player1Score int `json:"player1_score"`
-		Player2Score int `json:"player2_score"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request requests.Score
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
 		return
 	}
 
-	var match models.Match
-	if err := h.DB.First(&match, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch match"})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-	if match.Status != models.MatchStatusStarted {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Match must be started to update score"})
-		return
-	}
-
-	match.Player1Score = request.Player1Score
-	match.Player2Score = request.Player2Score
-
-	if err := h.DB.Save(&match).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update match score"})
+	match, err := h.Matches.UpdateScore(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), version, request.Player1Score, request.Player2Score)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, match)
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
 }
 
 func (h *MatchHandler) FinishMatch(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var match models.Match
-	if err := h.DB.First(&match, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Match not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch match"})
-		return
-	}
-
-	if match.Status != models.MatchStatusStarted {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Match must be started to finish it"})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-	// Determine winner based on score
-	var winner *string
-	if match.Player1Score > match.Player2Score {
-		winner = &match.Player1
-	} else if match.Player2Score > match.Player1Score {
-		winner = &match.Player2
-	} else {
-		// Draw - winner stays nil
-		winner = nil
-	}
-
-	now := time.Now()
-	match.Status = models.MatchStatusFinished
-	match.Winner = winner
-	match.FinishedAt = &now
-
-	if err := h.DB.Save(&match).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to finish match"})
+	match, err := h.Matches.Finish(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), version)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, match)
+	setETag(c, match.Version)
+	respond(c, h.DB, http.StatusOK, match)
 }
-
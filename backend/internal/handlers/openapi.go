@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetOpenAPI serves the OpenAPI document of the API version the request was made to
+func GetOpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, openapi.Spec(middleware.CurrentAPIVersion(c)))
+}
+
+// GetDocs serves a page that renders the OpenAPI document
+func GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.DocsPage)
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type OrganizationHandler struct {
+	DB            *gorm.DB
+	Organizations service.OrganizationService
+}
+
+func NewOrganizationHandler(db *gorm.DB) *OrganizationHandler {
+	return &OrganizationHandler{DB: db, Organizations: service.NewOrganizationService(repository.NewStore(db))}
+}
+
+// GetMyOrganizations lists the organizations the caller is a member of
+func (h *OrganizationHandler) GetMyOrganizations(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	memberships, err := h.Organizations.Memberships(userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, memberships)
+}
+
+func (h *OrganizationHandler) CreateOrganization(c *gin.Context) {
+	var request requests.CreateOrganization
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	// The creator becomes the owner of the organization
+	userID, _ := middleware.CurrentUserID(c)
+	organization, err := h.Organizations.Create(userID, models.Organization{Name: request.Name})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, organization)
+}
+
+func (h *OrganizationHandler) GetMembers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	members, err := h.Organizations.Members(actor(c, h.DB), uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// SetMember adds a user by email or changes the role of an existing member
+func (h *OrganizationHandler) SetMember(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	var request requests.SetOrganizationMember
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	member, err := h.Organizations.SetMember(actor(c, h.DB), uint(id), normalizeEmail(request.Email), request.Role)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid user ID")
+		return
+	}
+
+	if err := h.Organizations.RemoveMember(actor(c, h.DB), uint(id), uint(userID)); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"scoretracker/backend/internal/access"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// requireRole writes an error response and returns false unless the caller has
+// at least the given role in the championship
+func requireRole(c *gin.Context, db *gorm.DB, championshipID uint, min models.ChampionshipRole) bool {
+	role, err := middleware.ResolveRole(c, db, championshipID)
+	if err != nil {
+		problem.Internal(c, "Failed to check permissions", err)
+		return false
+	}
+
+	if !role.AtLeast(min) {
+		problem.Abort(c, http.StatusForbidden, problem.CodeForbidden, "Insufficient permissions")
+		return false
+	}
+
+	return true
+}
+
+// requireOrganizationAdmin allows admins and owners of the caller's organization
+func requireOrganizationAdmin(c *gin.Context, db *gorm.DB) bool {
+	userID, _ := middleware.CurrentUserID(c)
+	role, err := access.OrganizationRole(db, userID, middleware.CurrentOrganizationID(c))
+	if err != nil {
+		problem.Internal(c, "Failed to check permissions", err)
+		return false
+	}
+
+	if !role.AtLeast(models.OrganizationRoleAdmin) {
+		problem.Abort(c, http.StatusForbidden, problem.CodeForbidden, "Insufficient permissions")
+		return false
+	}
+
+	return true
+}
@@ -3,289 +3,128 @@ package handlers
 import (
 	"net/http"
 	"strconv"
-	"strings"
 
-	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type PlayerHandler struct {
-	DB *gorm.DB
+	DB      *gorm.DB
+	Players service.PlayerService
+	Stats   service.StatsService
 }
 
 func NewPlayerHandler(db *gorm.DB) *PlayerHandler {
-	return &PlayerHandler{DB: db}
+	store := repository.NewStore(db)
+	return &PlayerHandler{DB: db, Players: service.NewPlayerService(store), Stats: service.NewStatsService(store)}
 }
 
+// GetAllPlayers lists the players, filtered by the championship_id and name
+// query parameters, where name matches any part of a player's name. API keys
+// list the players of their championship.
 func (h *PlayerHandler) GetAllPlayers(c *gin.Context) {
-	var players []models.Player
-	championshipID := c.Query("championship_id")
+	options, ok := listOptions(c)
+	if !ok {
+		return
+	}
 
-	query := h.DB
-	if championshipID != "" {
-		id, err := strconv.ParseUint(championshipID, 10, 32)
-		if err == nil {
-			// Filter players by championship using the join table
-			query = query.Joins("JOIN player_championships ON players.id = player_championships.player_id").
-				Where("player_championships.championship_id = ?", id)
-		}
+	filter := repository.PlayerFilter{Name: c.Query("name")}
+	if filter.ChampionshipID, ok = queryID(c, "championship_id", "Invalid championship ID"); !ok {
+		return
+	}
+	if filter.ChampionshipID, ok = keyChampionship(c, filter.ChampionshipID); !ok {
+		return
 	}
 
-	if err := query.Preload("Championships").Order("created_at DESC").Find(&players).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch players"})
+	players, page, err := h.Players.List(middleware.CurrentOrganizationID(c), filter, options)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	setPageHeaders(c, page)
 	c.JSON(http.StatusOK, players)
 }
 
 func (h *PlayerHandler) GetPlayer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var player models.Player
-	if err := h.DB.Preload("Championships").First(&player, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player"})
+	player, err := h.Players.Get(middleware.CurrentOrganizationID(c), uint(id))
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	setETag(c, player.Version)
 	c.JSON(http.StatusOK, player)
 }
 
 func (h *PlayerHandler) CreatePlayer(c *gin.Context) {
-	var request struct {
-		Name          string  `json:"name" binding:"required"`
-		ChampionshipIDs []uint `json:"championship_ids"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var request requests.CreatePlayer
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
 		return
 	}
 
-	if request.Name == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
-		return
-	}
-
-	player := models.Player{
-		Name: request.Name,
-	}
-
-	// If championships are provided, verify and assign them
-	if len(request.ChampionshipIDs) > 0 {
-		// Verify all championships exist and are not finalized
-		var championships []models.Championship
-		if err := h.DB.Where("id IN ?", request.ChampionshipIDs).Find(&championships).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify championships"})
-			return
-		}
-
-		if len(championships) != len(request.ChampionshipIDs) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "One or more championships not found"})
-			return
-		}
-
-		// Check if any championship is finalized
-		for _, champ := range championships {
-			if champ.Status == models.ChampionshipStatusFinalized {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot add players to a finalized championship"})
-				return
-			}
-		}
-
-		player.Championships = championships
-	}
-
-	if err := h.DB.Create(&player).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create player"})
+	player, err := h.Players.Create(middleware.CurrentOrganizationID(c), actor(c, h.DB), request.Name, request.ChampionshipIDs)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
-	h.DB.Preload("Championships").First(&player, player.ID)
+	setETag(c, player.Version)
 	c.JSON(http.StatusCreated, player)
 }
 
 func (h *PlayerHandler) UpdatePlayer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var player models.Player
-	if err := h.DB.Preload("Championships").First(&player, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player"})
+	var request requests.UpdatePlayer
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
 		return
 	}
 
-	var request struct {
-		Name          *string `json:"name"`
-		ChampionshipIDs []uint `json:"championship_ids"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	version, ok := ifMatch(c)
+	if !ok {
 		return
 	}
 
-
-	// Update name if provided
-	if request.Name != nil {
-		if err := h.DB.Model(&player).Update("name", *request.Name).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update player name"})
-			return
-		}
-	}
-
-	// Update championships - always process if field is present (even if empty array)
-	if request.ChampionshipIDs != nil {
-		// Get current championships
-		var currentChampionships []models.Championship
-		h.DB.Model(&player).Association("Championships").Find(&currentChampionships)
-		
-		// Create a map of current championship IDs for quick lookup
-		currentChampIDs := make(map[uint]bool)
-		for _, champ := range currentChampionships {
-			currentChampIDs[champ.ID] = true
-		}
-		
-		// Create a map of new championship IDs
-		newChampIDs := make(map[uint]bool)
-		for _, id := range request.ChampionshipIDs {
-			newChampIDs[id] = true
-		}
-		
-		// Find championships that are being removed (in current but not in new)
-		var removedChampIDs []uint
-		for id := range currentChampIDs {
-			if !newChampIDs[id] {
-				removedChampIDs = append(removedChampIDs, id)
-			}
-		}
-		
-		// Check if any removed championships are finalized
-		if len(removedChampIDs) > 0 {
-			var removedChampionships []models.Championship
-			if err := h.DB.Where("id IN ?", removedChampIDs).Find(&removedChampionships).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify removed championships"})
-				return
-			}
-			
-			for _, champ := range removedChampionships {
-				if champ.Status == models.ChampionshipStatusFinalized {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot remove players from a finalized championship"})
-					return
-				}
-			}
-		}
-
-		// Find championships that are being added (in new but not in current)
-		var addedChampIDs []uint
-		for _, id := range request.ChampionshipIDs {
-			if !currentChampIDs[id] {
-				addedChampIDs = append(addedChampIDs, id)
-			}
-		}
-		
-		// Check if any added championships are finalized
-		if len(addedChampIDs) > 0 {
-			var addedChampionships []models.Championship
-			if err := h.DB.Where("id IN ?", addedChampIDs).Find(&addedChampionships).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify added championships"})
-				return
-			}
-			
-			for _, champ := range addedChampionships {
-				if champ.Status == models.ChampionshipStatusFinalized {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot add players to a finalized championship"})
-					return
-				}
-			}
-		}
-
-		// Use GORM's Table method for direct SQL operations
-		// First, delete all existing associations for this player
-		result := h.DB.Table("player_championships").Where("player_id = ?", player.ID).Delete(nil)
-		if result.Error != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clear player championships: " + result.Error.Error()})
-			return
-		}
-		
-		// Then, insert the new associations (if any)
-		if len(request.ChampionshipIDs) > 0 {
-			// Verify all championships exist
-			var count int64
-			if err := h.DB.Model(&models.Championship{}).Where("id IN ?", request.ChampionshipIDs).Count(&count).Error; err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify championships"})
-				return
-			}
-			
-			if int(count) != len(request.ChampionshipIDs) {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "One or more championships not found"})
-				return
-			}
-			
-			// Insert new associations using GORM's Table method
-			for _, champID := range request.ChampionshipIDs {
-				if err := h.DB.Table("player_championships").Create(map[string]interface{}{
-					"player_id":       player.ID,
-					"championship_id": champID,
-				}).Error; err != nil {
-					// Ignore duplicate key errors (ON CONFLICT DO NOTHING equivalent)
-					if !strings.Contains(err.Error(), "duplicate key") && !strings.Contains(err.Error(), "UNIQUE constraint") {
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add player championship: " + err.Error()})
-						return
-					}
-				}
-			}
-		}
-	}
-
-	// Reload player with associations
-	if err := h.DB.Preload("Championships").First(&player, player.ID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload player"})
+	player, err := h.Players.Update(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id), version, request.Name, request.ChampionshipIDs)
+	if err != nil {
+		respondError(c, err)
 		return
 	}
 
+	setETag(c, player.Version)
 	c.JSON(http.StatusOK, player)
 }
 
 func (h *PlayerHandler) DeletePlayer(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
 		return
 	}
 
-	var player models.Player
-	if err := h.DB.First(&player, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player"})
-		return
-	}
-
-	if err := h.DB.Delete(&player).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete player"})
+	if err := h.Players.Delete(middleware.CurrentOrganizationID(c), actor(c, h.DB), uint(id)); err != nil {
+		respondError(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Player deleted successfully"})
 }
-
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/projections"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxSimulations = 100000
+
+// GetProjections simulates the championship's remaining matches and returns
+// the probability of every player finishing in each position. Match
+// probabilities come from the players' historic results in the organization.
+func (h *ChampionshipHandler) GetProjections(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	simulations := projections.DefaultSimulations
+	if value := c.Query("simulations"); value != "" {
+		simulations, err = strconv.Atoi(value)
+		if err != nil || simulations < 1 || simulations > maxSimulations {
+			problem.Invalid(c, problem.FieldError{Field: "simulations", Code: problem.FieldOutOfRange, Message: fmt.Sprintf("simulations must be between 1 and %d", maxSimulations)})
+			return
+		}
+	}
+
+	result, err := h.Projections.Project(middleware.CurrentOrganizationID(c), uint(id), simulations)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
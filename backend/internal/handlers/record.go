@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type RecordHandler struct {
+	DB    *gorm.DB
+	Stats service.StatsService
+}
+
+func NewRecordHandler(db *gorm.DB) *RecordHandler {
+	return &RecordHandler{DB: db, Stats: service.NewStatsService(repository.NewStore(db))}
+}
+
+// GetRecords returns the hall of fame of the caller's organization, each record
+// with the match that set it
+func (h *RecordHandler) GetRecords(c *gin.Context) {
+	records, err := h.Stats.Records(middleware.CurrentOrganizationID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, h.DB, http.StatusOK, records)
+}
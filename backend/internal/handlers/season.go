@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type SeasonHandler struct {
+	DB            *gorm.DB
+	Seasons       service.SeasonService
+	Championships service.ChampionshipService
+}
+
+func NewSeasonHandler(db *gorm.DB) *SeasonHandler {
+	store := repository.NewStore(db)
+	return &SeasonHandler{
+		DB:            db,
+		Seasons:       service.NewSeasonService(store),
+		Championships: service.NewChampionshipService(store),
+	}
+}
+
+func (h *SeasonHandler) GetAllSeasons(c *gin.Context) {
+	seasons, err := h.Seasons.List(middleware.CurrentOrganizationID(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, seasons)
+}
+
+func (h *SeasonHandler) GetSeason(c *gin.Context) {
+	id, ok := seasonParam(c)
+	if !ok {
+		return
+	}
+
+	season, err := h.Seasons.Get(middleware.CurrentOrganizationID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, season.Version)
+	c.JSON(http.StatusOK, season)
+}
+
+func (h *SeasonHandler) CreateSeason(c *gin.Context) {
+	if !requireOrganizationAdmin(c, h.DB) {
+		return
+	}
+
+	var request requests.SaveSeason
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	var season models.Season
+	request.Apply(&season)
+
+	season, err := h.Seasons.Create(middleware.CurrentOrganizationID(c), season)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, season.Version)
+	c.JSON(http.StatusCreated, season)
+}
+
+func (h *SeasonHandler) UpdateSeason(c *gin.Context) {
+	if !requireOrganizationAdmin(c, h.DB) {
+		return
+	}
+
+	id, ok := seasonParam(c)
+	if !ok {
+		return
+	}
+
+	var request requests.SaveSeason
+	if err := requests.Bind(c, &request); err != nil {
+		problem.Binding(c, err)
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	season, err := h.Seasons.Update(middleware.CurrentOrganizationID(c), id, version, request.Apply)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, season.Version)
+	c.JSON(http.StatusOK, season)
+}
+
+func (h *SeasonHandler) DeleteSeason(c *gin.Context) {
+	if !requireOrganizationAdmin(c, h.DB) {
+		return
+	}
+
+	id, ok := seasonParam(c)
+	if !ok {
+		return
+	}
+
+	// The championships themselves stay, they just no longer belong to a season
+	if err := h.Seasons.Delete(middleware.CurrentOrganizationID(c), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Season deleted successfully"})
+}
+
+// AddChampionship adds a championship to the season or changes its weight.
+// If-Match refers to the version of the championship.
+func (h *SeasonHandler) AddChampionship(c *gin.Context) {
+	if !requireOrganizationAdmin(c, h.DB) {
+		return
+	}
+
+	seasonID, ok := seasonParam(c)
+	if !ok {
+		return
+	}
+
+	championshipID, ok := championshipParam(c)
+	if !ok {
+		return
+	}
+
+	var request requests.SeasonChampionship
+
+	// The body is optional, the weight defaults to 1
+	if c.Request.ContentLength != 0 {
+		if err := requests.Bind(c, &request); err != nil {
+			problem.Binding(c, err)
+			return
+		}
+	}
+
+	weight := 1.0
+	if request.Weight != nil {
+		weight = *request.Weight
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	championship, err := h.Championships.JoinSeason(middleware.CurrentOrganizationID(c), championshipID, version, seasonID, weight)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, championship.Version)
+	c.JSON(http.StatusOK, championship)
+}
+
+// RemoveChampionship removes a championship from the season. If-Match refers
+// to the version of the championship.
+func (h *SeasonHandler) RemoveChampionship(c *gin.Context) {
+	if !requireOrganizationAdmin(c, h.DB) {
+		return
+	}
+
+	seasonID, ok := seasonParam(c)
+	if !ok {
+		return
+	}
+
+	championshipID, ok := championshipParam(c)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatch(c)
+	if !ok {
+		return
+	}
+
+	championship, err := h.Championships.LeaveSeason(middleware.CurrentOrganizationID(c), championshipID, version, seasonID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	setETag(c, championship.Version)
+	c.JSON(http.StatusOK, gin.H{"message": "Championship removed from season"})
+}
+
+// GetSeasonStandings sums the weighted championship points of every player,
+// leaving out each player's worst results as configured by drop_worst
+func (h *SeasonHandler) GetSeasonStandings(c *gin.Context) {
+	id, ok := seasonParam(c)
+	if !ok {
+		return
+	}
+
+	seasonStandings, err := h.Seasons.Standings(middleware.CurrentOrganizationID(c), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, seasonStandings)
+}
+
+// seasonParam parses the :id route parameter
+func seasonParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// championshipParam parses the :championshipId route parameter
+func championshipParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("championshipId"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid championship ID")
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"scoretracker/backend/internal/access"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// requestActor is the caller of a request, as seen by the services
+type requestActor struct {
+	c  *gin.Context
+	db *gorm.DB
+}
+
+func actor(c *gin.Context, db *gorm.DB) service.Actor {
+	return requestActor{c: c, db: db}
+}
+
+func (a requestActor) Role(championshipID uint) (models.ChampionshipRole, error) {
+	return middleware.ResolveRole(a.c, a.db, championshipID)
+}
+
+// OrganizationRole is empty for API keys, which belong to a championship
+func (a requestActor) OrganizationRole(organizationID uint) (models.OrganizationRole, error) {
+	userID, ok := middleware.CurrentUserID(a.c)
+	if !ok {
+		return "", nil
+	}
+	return access.OrganizationRole(a.db, userID, organizationID)
+}
+
+func (a requestActor) Player() (*models.Player, error) {
+	userID, ok := middleware.CurrentUserID(a.c)
+	if !ok {
+		return nil, nil
+	}
+	return access.LinkedPlayer(a.db, userID)
+}
+
+var errorStatus = map[service.ErrorKind]int{
+	service.KindInvalid:   http.StatusBadRequest,
+	service.KindNotFound:  http.StatusNotFound,
+	service.KindForbidden: http.StatusForbidden,
+	service.KindStale:     http.StatusPreconditionFailed,
+	service.KindConflict:  http.StatusConflict,
+	service.KindInternal:  http.StatusInternalServerError,
+}
+
+// respondError writes the problem for an error returned by a service.
+// Internal errors are logged, only their message reaches the client.
+func respondError(c *gin.Context, err error) {
+	var serviceErr *service.Error
+	if !errors.As(err, &serviceErr) {
+		problem.Internal(c, "Internal server error", err)
+		return
+	}
+
+	switch {
+	case serviceErr.Kind == service.KindInternal:
+		problem.Internal(c, serviceErr.Message, serviceErr.Err)
+	case serviceErr.Field != "":
+		problem.Invalid(c, problem.FieldError{Field: serviceErr.Field, Code: serviceErr.Code, Message: serviceErr.Message})
+	default:
+		problem.Abort(c, errorStatus[serviceErr.Kind], serviceErr.Code, serviceErr.Message)
+	}
+}
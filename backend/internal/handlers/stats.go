@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlayerStats computes statistics from the player's finished matches,
+// optionally limited by championship_id and a from/to date range
+func (h *PlayerHandler) GetPlayerStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	filter, ok := resultFilter(c)
+	if !ok {
+		return
+	}
+
+	player, playerStats, err := h.Stats.PlayerStats(middleware.CurrentOrganizationID(c), uint(id), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": player,
+		"stats":  playerStats,
+	})
+}
+
+// GetHeadToHead lists the finished matches between two players across all
+// championships, newest first, together with the record from the first
+// player's point of view. Accepts the same filters as GetPlayerStats.
+func (h *PlayerHandler) GetHeadToHead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+		return
+	}
+
+	otherID, err := strconv.ParseUint(c.Param("otherId"), 10, 32)
+	if err != nil {
+		problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid opponent ID")
+		return
+	}
+
+	filter, ok := resultFilter(c)
+	if !ok {
+		return
+	}
+
+	headToHead, err := h.Stats.HeadToHead(middleware.CurrentOrganizationID(c), uint(id), uint(otherID), filter)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, h.DB, http.StatusOK, gin.H{
+		"player":   headToHead.Player,
+		"opponent": headToHead.Opponent,
+		"record":   headToHead.Record,
+		"matches":  headToHead.Matches,
+	})
+}
+
+// resultFilter reads the championship_id, from and to query parameters.
+// Dates are either YYYY-MM-DD, where to is inclusive, or RFC 3339 timestamps.
+func resultFilter(c *gin.Context) (repository.ResultFilter, bool) {
+	var filter repository.ResultFilter
+
+	if value := c.Query("championship_id"); value != "" {
+		championshipID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid championship ID")
+			return filter, false
+		}
+		id := uint(championshipID)
+		filter.ChampionshipID = &id
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, _, err := parseDateParam(value)
+		if err != nil {
+			problem.Invalid(c, problem.FieldError{Field: "from", Code: problem.FieldInvalidFormat, Message: "Invalid from date, use YYYY-MM-DD or RFC 3339"})
+			return filter, false
+		}
+		filter.From = &from
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, dateOnly, err := parseDateParam(value)
+		if err != nil {
+			problem.Invalid(c, problem.FieldError{Field: "to", Code: problem.FieldInvalidFormat, Message: "Invalid to date, use YYYY-MM-DD or RFC 3339"})
+			return filter, false
+		}
+		// A date includes the whole day
+		if dateOnly {
+			to = to.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		filter.To = &to
+	}
+
+	return filter, true
+}
+
+func parseDateParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(models.DateLayout, value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// keyChampionship restricts the championship filter of a list to the
+// championship of the caller's API key, as API keys only read their own
+// championship. Filtering on another championship is forbidden.
+func keyChampionship(c *gin.Context, championshipID *uint) (*uint, bool) {
+	key, ok := middleware.CurrentAPIKey(c)
+	if !ok {
+		return championshipID, true
+	}
+
+	if championshipID != nil && *championshipID != key.ChampionshipID {
+		problem.Abort(c, http.StatusForbidden, problem.CodeForbidden, "API keys can only read their own championship")
+		return nil, false
+	}
+	return &key.ChampionshipID, true
+}
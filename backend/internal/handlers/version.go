@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"reflect"
+
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/apiv2"
+	"scoretracker/backend/internal/middleware"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/service"
+	"scoretracker/backend/internal/standings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// adapter converts a value a handler responds with to the representation of
+// an API version
+type adapter func(r *representation, value interface{}) interface{}
+
+// adapters convert the values whose representation depends on the version,
+// by version and type. Values without an adapter are sent as they are, slices
+// and gin.H bodies are converted element by element.
+var adapters = map[int]map[reflect.Type]adapter{
+	1: {
+		reflect.TypeOf(standings.Row{}): func(r *representation, value interface{}) interface{} {
+			return value.(standings.Row).Standing
+		},
+	},
+	2: {
+		reflect.TypeOf(models.Match{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewMatch(value.(models.Match), r.player)
+		},
+		reflect.TypeOf(models.Championship{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewChampionship(value.(models.Championship), r.player)
+		},
+		reflect.TypeOf(service.Draw{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewDraw(value.(service.Draw), r.player)
+		},
+		reflect.TypeOf(models.Record{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewRecord(value.(models.Record), r.player)
+		},
+		reflect.TypeOf(achievements.Earned{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewAchievement(value.(achievements.Earned), r.player)
+		},
+		reflect.TypeOf(standings.Row{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewStanding(value.(standings.Row), middleware.CurrentOrganizationID(r.c), r.player)
+		},
+		reflect.TypeOf(service.StandingsSnapshot{}): func(r *representation, value interface{}) interface{} {
+			return apiv2.NewStandingsSnapshot(value.(service.StandingsSnapshot), middleware.CurrentOrganizationID(r.c), r.player)
+		},
+	},
+}
+
+// respond sends a body in the representation of the API version the request
+// was made to. Handlers whose responses differ between versions use it
+// instead of c.JSON, so every version shares the same handler.
+func respond(c *gin.Context, db *gorm.DB, status int, body interface{}) {
+	r := &representation{c: c, db: db, adapters: adapters[middleware.CurrentAPIVersion(c)]}
+	result := r.convert(body)
+	if r.err != nil {
+		problem.Internal(c, "Failed to fetch players", r.err)
+		return
+	}
+	c.JSON(status, result)
+}
+
+type representation struct {
+	c        *gin.Context
+	db       *gorm.DB
+	adapters map[reflect.Type]adapter
+	// Player IDs by name, per organization
+	players map[uint]map[string]uint
+	err     error
+}
+
+func (r *representation) convert(value interface{}) interface{} {
+	if adapt, ok := r.adapters[reflect.TypeOf(value)]; ok {
+		return adapt(r, value)
+	}
+
+	if body, ok := value.(gin.H); ok {
+		result := make(gin.H, len(body))
+		for key, field := range body {
+			result[key] = r.convert(field)
+		}
+		return result
+	}
+
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice {
+		if adapt, ok := r.adapters[v.Type().Elem()]; ok {
+			result := make([]interface{}, v.Len())
+			for i := range result {
+				result[i] = adapt(r, v.Index(i).Interface())
+			}
+			return result
+		}
+	}
+
+	return value
+}
+
+// player refers to a player by name. The players of an organization are
+// loaded once, including deleted ones, as matches keep their names.
+func (r *representation) player(organizationID uint, name string) apiv2.PlayerRef {
+	ref := apiv2.PlayerRef{Name: name}
+
+	if r.players == nil {
+		r.players = map[uint]map[string]uint{}
+	}
+	ids, ok := r.players[organizationID]
+	if !ok {
+		var players []models.Player
+		if err := r.db.Unscoped().Where("organization_id = ?", organizationID).Find(&players).Error; err != nil {
+			r.err = err
+		}
+		ids = make(map[string]uint, len(players))
+		for _, player := range players {
+			// A current player wins over a deleted one of the same name
+			if _, taken := ids[player.Name]; !taken || !player.DeletedAt.Valid {
+				ids[player.Name] = player.ID
+			}
+		}
+		r.players[organizationID] = ids
+	}
+
+	if id, ok := ids[name]; ok {
+		ref.ID = &id
+	}
+	return ref
+}
@@ -0,0 +1,99 @@
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"scoretracker/backend/internal/models"
+)
+
+// MatchDuration is the assumed length of a match, used for DTEND
+const MatchDuration = time.Hour
+
+const (
+	productID   = "-//scoretracker//scoretracker-api//EN"
+	uidDomain   = "scoretracker"
+	utcLayout   = "20060102T150405Z"
+	maxLineSize = 75
+)
+
+// MatchUID returns the stable identifier of a match event. It only depends on
+// the match ID, so a rescheduled match replaces the existing calendar entry.
+func MatchUID(match models.Match) string {
+	return fmt.Sprintf("match-%d@%s", match.ID, uidDomain)
+}
+
+// Calendar renders an RFC 5545 calendar with one event per scheduled match,
+// generated at the given time. Matches without a scheduled time are skipped.
+// The sequence of an event is the version of its match, so clients replace
+// the events of matches that changed.
+func Calendar(name string, matches []models.Match, generatedAt time.Time) []byte {
+	stamp := generatedAt.UTC().Format(utcLayout)
+
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+productID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText(name))
+
+	for _, match := range matches {
+		if match.ScheduledAt == nil {
+			continue
+		}
+		start := match.ScheduledAt.UTC()
+		summary := fmt.Sprintf("%s: %s vs %s", match.Game, match.Player1, match.Player2)
+		description := fmt.Sprintf("Status: %s", match.Status)
+		if match.Status == models.MatchStatusFinished {
+			description += fmt.Sprintf("\nResult: %d - %d", match.Player1Score, match.Player2Score)
+		}
+
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+MatchUID(match))
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, fmt.Sprintf("SEQUENCE:%d", match.Version))
+		writeLine(&b, "LAST-MODIFIED:"+match.UpdatedAt.UTC().Format(utcLayout))
+		writeLine(&b, "DTSTART:"+start.Format(utcLayout))
+		writeLine(&b, "DTEND:"+start.Add(MatchDuration).Format(utcLayout))
+		writeLine(&b, "SUMMARY:"+escapeText(summary))
+		writeLine(&b, "DESCRIPTION:"+escapeText(description))
+		if match.Status == models.MatchStatusFinished {
+			writeLine(&b, "STATUS:CONFIRMED")
+		} else {
+			writeLine(&b, "STATUS:TENTATIVE")
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// writeLine terminates a content line with CRLF and folds it at 75 octets
+// without splitting multi-byte UTF-8 characters
+func writeLine(b *strings.Builder, line string) {
+	size := 0
+	for _, r := range line {
+		runeSize := len(string(r))
+		if size+runeSize > maxLineSize {
+			b.WriteString("\r\n ")
+			size = 1
+		}
+		b.WriteRune(r)
+		size += runeSize
+	}
+	b.WriteString("\r\n")
+}
+
+func escapeText(text string) string {
+	replacer := strings.NewReplacer(
+		"\\", "\\\\",
+		";", "\\;",
+		",", "\\,",
+		"\r\n", "\\n",
+		"\n", "\\n",
+	)
+	return replacer.Replace(text)
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AutoConfirmResults makes reported results official once the championship's
+// confirmation timeout has passed without a response from the opponent
+func AutoConfirmResults(db *gorm.DB, now time.Time) (int, error) {
+	var matches []models.Match
+	if err := db.Preload("Championship").
+		Where("status = ? AND reported_at IS NOT NULL", models.MatchStatusPendingConfirmation).
+		Find(&matches).Error; err != nil {
+		return 0, err
+	}
+
+	confirmed := 0
+	for _, match := range matches {
+		timeout := time.Duration(match.Championship.ConfirmationTimeoutHours) * time.Hour
+		if match.ReportedAt.Add(timeout).After(now) {
+			continue
+		}
+
+		match.Finish(now)
+		// Guard on the status so a concurrent confirm or dispute wins
+		result := db.Model(&models.Match{}).
+			Where("id = ? AND status = ?", match.ID, models.MatchStatusPendingConfirmation).
+			Updates(map[string]interface{}{
+				"status":      match.Status,
+				"winner":      match.Winner,
+				"finished_at": match.FinishedAt,
+				"version":     gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return confirmed, result.Error
+		}
+		confirmed += int(result.RowsAffected)
+		if result.RowsAffected > 0 {
+			ResultFinished(db, match)
+		}
+	}
+
+	return confirmed, nil
+}
+
+// RunAutoConfirm periodically auto-confirms results until the process exits
+func RunAutoConfirm(db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		confirmed, err := AutoConfirmResults(db, now)
+		if err != nil {
+			log.Printf("Failed to auto-confirm results: %v", err)
+			continue
+		}
+		if confirmed > 0 {
+			log.Printf("Auto-confirmed %d reported results", confirmed)
+		}
+	}
+}
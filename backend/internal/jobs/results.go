@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/records"
+
+	"gorm.io/gorm"
+)
+
+// retryDelays are the waits before each retry of a failed update of the data
+// derived from a result
+var retryDelays = []time.Duration{time.Second, 10 * time.Second, time.Minute, 10 * time.Minute, time.Hour}
+
+// ResultFinished updates the data derived from official results once a match
+// is finished. The result itself is already saved, so a failed update is
+// retried in the background instead of failing the request.
+func ResultFinished(db *gorm.DB, match models.Match) {
+	if err := updateDerived(db, match); err != nil {
+		log.Printf("Failed to update records and badges for match %d, retrying: %v", match.ID, err)
+		go retryDerived(db, match)
+	}
+}
+
+func updateDerived(db *gorm.DB, match models.Match) error {
+	if err := records.Update(db, match); err != nil {
+		return err
+	}
+	_, err := achievements.AwardMatch(db, match)
+	return err
+}
+
+// retryDerived repeats the update of a match until it succeeds or the retries
+// are used up. Updates can be repeated, so a late retry does no harm.
+func retryDerived(db *gorm.DB, match models.Match) {
+	for attempt, delay := range retryDelays {
+		time.Sleep(delay)
+		err := updateDerived(db, match)
+		if err == nil {
+			return
+		}
+		log.Printf("Retry %d of updating records and badges for match %d failed: %v", attempt+1, match.ID, err)
+	}
+	log.Printf("Gave up updating records and badges for match %d", match.ID)
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	userIDKey = "user_id"
+	apiKeyKey = "api_key"
+
+	// APIKeyQuery is the query parameter feeds accept API keys in
+	APIKeyQuery = "api_key"
+
+	// lastUsedResolution limits how often last_used_at is written for busy keys
+	lastUsedResolution = time.Minute
+)
+
+// RequireAuth rejects requests without a valid "Authorization: Bearer <token>"
+// header. The token is either a user JWT or an API key; the authenticated user
+// ID or API key is stored in the gin context.
+func RequireAuth(db *gorm.DB, tokens *auth.TokenService) gin.HandlerFunc {
+	return requireAuth(db, tokens, false)
+}
+
+// RequireFeedAuth is RequireAuth for feeds such as calendars. Their clients
+// cannot send headers, so GET requests may pass an API key as the api_key
+// query parameter instead. Query strings end up in logs and browser
+// histories, so no other endpoint accepts it.
+func RequireFeedAuth(db *gorm.DB, tokens *auth.TokenService) gin.HandlerFunc {
+	return requireAuth(db, tokens, true)
+}
+
+func requireAuth(db *gorm.DB, tokens *auth.TokenService, queryKey bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, found := strings.CutPrefix(header, "Bearer ")
+
+		if !found && queryKey && c.Request.Method == http.MethodGet && strings.HasPrefix(c.Query(APIKeyQuery), auth.APIKeyPrefix) {
+			token, found = c.Query(APIKeyQuery), true
+		}
+
+		if !found || token == "" {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeAuthenticationRequired, "Authentication required")
+			return
+		}
+
+		if strings.HasPrefix(token, auth.APIKeyPrefix) {
+			authenticateAPIKey(c, db, token)
+			return
+		}
+
+		userID, _, err := tokens.ParseAccessToken(token)
+		if err != nil {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidToken, "Invalid or expired token")
+			return
+		}
+
+		c.Set(userIDKey, userID)
+		c.Next()
+	}
+}
+
+// RequireUser rejects API keys on endpoints that act on behalf of a user
+func RequireUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentUserID(c); !ok {
+			problem.Abort(c, http.StatusForbidden, problem.CodeUserRequired, "This endpoint requires a user account")
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUserID returns the user authenticated by RequireAuth
+func CurrentUserID(c *gin.Context) (uint, bool) {
+	value, exists := c.Get(userIDKey)
+	if !exists {
+		return 0, false
+	}
+	userID, ok := value.(uint)
+	return userID, ok
+}
+
+// CurrentAPIKey returns the API key authenticated by RequireAuth, if any
+func CurrentAPIKey(c *gin.Context) (*models.APIKey, bool) {
+	value, exists := c.Get(apiKeyKey)
+	if !exists {
+		return nil, false
+	}
+	key, ok := value.(*models.APIKey)
+	return key, ok
+}
+
+func authenticateAPIKey(c *gin.Context, db *gorm.DB, token string) {
+	now := time.Now()
+
+	var key models.APIKey
+	if err := db.Where("key_hash = ? AND revoked_at IS NULL", auth.HashToken(token)).First(&key).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidAPIKey, "Invalid API key")
+			return
+		}
+		problem.Internal(c, "Failed to verify API key", err)
+		return
+	}
+
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(now) {
+		problem.Abort(c, http.StatusUnauthorized, problem.CodeAPIKeyExpired, "API key has expired")
+		return
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedResolution {
+		// Tracking usage must not fail the request
+		db.Model(&key).UpdateColumn("last_used_at", now)
+		key.LastUsedAt = &now
+	}
+
+	c.Set(apiKeyKey, &key)
+	c.Next()
+}
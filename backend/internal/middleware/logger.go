@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,7 +18,7 @@ func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
+		raw := RedactQuery(c.Request.URL.RawQuery)
 
 		c.Next()
 
@@ -39,3 +41,47 @@ func Logger() gin.HandlerFunc {
 	}
 }
 
+
+// LogFormatter formats requests like the default logger of gin, with the API
+// keys in query strings redacted
+func LogFormatter(param gin.LogFormatterParams) string {
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+	if path, raw, found := strings.Cut(param.Path, "?"); found {
+		param.Path = path + "?" + RedactQuery(raw)
+	}
+
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		param.Path,
+		param.ErrorMessage,
+	)
+}
+
+// RedactQuery replaces the values of API keys in a raw query string, keeping
+// everything else as it was sent
+func RedactQuery(raw string) string {
+	if !strings.Contains(raw, APIKeyQuery) {
+		return raw
+	}
+
+	parameters := strings.Split(raw, "&")
+	for i, parameter := range parameters {
+		if name, _, found := strings.Cut(parameter, "="); found && name == APIKeyQuery {
+			parameters[i] = name + "=REDACTED"
+		}
+	}
+	return strings.Join(parameters, "&")
+}
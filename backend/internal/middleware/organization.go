@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/access"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	organizationIDKey = "organization_id"
+
+	// OrganizationHeader selects the organization for users that belong to several
+	OrganizationHeader = "X-Organization-ID"
+)
+
+// RequireOrganization resolves the organization the request acts in. API keys
+// act in the organization of their championship; users select one with the
+// X-Organization-ID header, which may be omitted if they belong to only one.
+// Must run after RequireAuth.
+func RequireOrganization(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := CurrentAPIKey(c); ok {
+			var championship models.Championship
+			if err := db.Select("id", "organization_id").First(&championship, key.ChampionshipID).Error; err != nil {
+				problem.Abort(c, http.StatusUnauthorized, problem.CodeInvalidAPIKey, "API key championship no longer exists")
+				return
+			}
+			c.Set(organizationIDKey, championship.OrganizationID)
+			c.Next()
+			return
+		}
+
+		userID, ok := CurrentUserID(c)
+		if !ok {
+			problem.Abort(c, http.StatusUnauthorized, problem.CodeAuthenticationRequired, "Authentication required")
+			return
+		}
+
+		if header := c.GetHeader(OrganizationHeader); header != "" {
+			organizationID, err := strconv.ParseUint(header, 10, 32)
+			if err != nil {
+				problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid organization ID")
+				return
+			}
+
+			role, err := access.OrganizationRole(db, userID, uint(organizationID))
+			if err != nil {
+				problem.Internal(c, "Failed to check organization membership", err)
+				return
+			}
+			if role == "" {
+				problem.Abort(c, http.StatusForbidden, problem.CodeNotOrganizationMember, "Not a member of this organization")
+				return
+			}
+
+			c.Set(organizationIDKey, uint(organizationID))
+			c.Next()
+			return
+		}
+
+		var memberships []models.OrganizationMember
+		if err := db.Where("user_id = ?", userID).Limit(2).Find(&memberships).Error; err != nil {
+			problem.Internal(c, "Failed to check organization membership", err)
+			return
+		}
+
+		switch len(memberships) {
+		case 0:
+			problem.Abort(c, http.StatusForbidden, problem.CodeNotOrganizationMember, "User is not a member of any organization")
+			return
+		case 1:
+			c.Set(organizationIDKey, memberships[0].OrganizationID)
+			c.Next()
+		default:
+			problem.Abort(c, http.StatusBadRequest, problem.CodeOrganizationRequired, "User belongs to several organizations, set the "+OrganizationHeader+" header")
+		}
+	}
+}
+
+// CurrentOrganizationID returns the organization resolved by RequireOrganization
+func CurrentOrganizationID(c *gin.Context) uint {
+	value, _ := c.Get(organizationIDKey)
+	organizationID, _ := value.(uint)
+	return organizationID
+}
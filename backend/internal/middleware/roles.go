@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"scoretracker/backend/internal/access"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const roleKey = "championship_role"
+
+// RequireChampionshipRole checks the caller's role in the championship given by
+// the :id route parameter. Must run after RequireAuth and RequireOrganization.
+func RequireChampionshipRole(db *gorm.DB, min models.ChampionshipRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+			return
+		}
+
+		var championship models.Championship
+		if err := db.Select("id").Where("organization_id = ?", CurrentOrganizationID(c)).First(&championship, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				problem.Abort(c, http.StatusNotFound, problem.CodeChampionshipNotFound, "Championship not found")
+				return
+			}
+			problem.Internal(c, "Failed to fetch championship", err)
+			return
+		}
+
+		authorize(c, db, championship.ID, min)
+	}
+}
+
+// RequireMatchRole checks the caller's role in the championship of the match
+// given by the :id route parameter. Must run after RequireAuth and RequireOrganization.
+func RequireMatchRole(db *gorm.DB, min models.ChampionshipRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			problem.Abort(c, http.StatusBadRequest, problem.CodeInvalidID, "Invalid ID")
+			return
+		}
+
+		var match models.Match
+		if err := db.Select("id", "championship_id").Where("organization_id = ?", CurrentOrganizationID(c)).First(&match, id).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				problem.Abort(c, http.StatusNotFound, problem.CodeMatchNotFound, "Match not found")
+				return
+			}
+			problem.Internal(c, "Failed to fetch match", err)
+			return
+		}
+
+		authorize(c, db, match.ChampionshipID, min)
+	}
+}
+
+// CurrentRole returns the championship role resolved by RequireChampionshipRole or RequireMatchRole
+func CurrentRole(c *gin.Context) models.ChampionshipRole {
+	value, _ := c.Get(roleKey)
+	role, _ := value.(models.ChampionshipRole)
+	return role
+}
+
+// ResolveRole returns the caller's role in a championship. API keys act with
+// the role of their scope, but only in the championship they were created for.
+func ResolveRole(c *gin.Context, db *gorm.DB, championshipID uint) (models.ChampionshipRole, error) {
+	if key, ok := CurrentAPIKey(c); ok {
+		if key.ChampionshipID != championshipID {
+			return "", nil
+		}
+		return key.Scope.Role(), nil
+	}
+
+	userID, ok := CurrentUserID(c)
+	if !ok {
+		return "", nil
+	}
+	return access.ChampionshipRole(db, userID, championshipID)
+}
+
+func authorize(c *gin.Context, db *gorm.DB, championshipID uint, min models.ChampionshipRole) {
+	role, err := ResolveRole(c, db, championshipID)
+	if err != nil {
+		problem.Internal(c, "Failed to check permissions", err)
+		return
+	}
+
+	if !role.AtLeast(min) {
+		problem.Abort(c, http.StatusForbidden, problem.CodeForbidden, "Insufficient permissions")
+		return
+	}
+
+	c.Set(roleKey, role)
+	c.Next()
+}
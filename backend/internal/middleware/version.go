@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiVersionKey = "api_version"
+
+// APIVersion records the version of the API a route group serves
+func APIVersion(version int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(apiVersionKey, version)
+		c.Next()
+	}
+}
+
+// CurrentAPIVersion returns the version of the API the request was made to.
+// Routes outside a versioned group serve version 1.
+func CurrentAPIVersion(c *gin.Context) int {
+	if version, ok := c.Get(apiVersionKey); ok {
+		return version.(int)
+	}
+	return 1
+}
+
+// Deprecated announces on every response that the routes under prefix are
+// deprecated since the given time (RFC 9745) and links to the same route under
+// successor. A non-zero sunset is sent as the time the routes stop working
+// (RFC 8594).
+func Deprecated(prefix string, successor string, since time.Time, sunset time.Time) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", fmt.Sprintf("@%d", since.Unix()))
+		if !sunset.IsZero() {
+			header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		path := successor + strings.TrimPrefix(c.Request.URL.Path, prefix)
+		header.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, path))
+		c.Next()
+	}
+}
@@ -0,0 +1,463 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// initialSchema creates the schema as it was when versioned migrations were
+// introduced. Databases created before that are brought to the same state:
+// data from the single-championship era (scores and matches without a
+// championship) moves into a default championship, and data from before
+// organizations existed moves into a default organization.
+//
+// The structs below are a frozen copy of the models at that time. Later
+// schema changes belong in new migrations, not here.
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&season{}, &championship{}, &player{}, &playerUnavailability{}); err != nil {
+			return err
+		}
+
+		if err := convertLegacyData(tx); err != nil {
+			return err
+		}
+
+		if err := tx.AutoMigrate(
+			&match{},
+			&user{},
+			&refreshToken{},
+			&championshipMember{},
+			&apiKey{},
+			&organization{},
+			&organizationMember{},
+			&record{},
+			&achievement{},
+			&championshipSeed{},
+		); err != nil {
+			return err
+		}
+
+		if err := alignMatchColumns(tx); err != nil {
+			return err
+		}
+
+		return adoptIntoDefaultOrganization(tx)
+	},
+	Down: func(tx *gorm.DB) error {
+		// Referencing tables first
+		return tx.Migrator().DropTable(
+			"achievements",
+			"records",
+			"championship_seeds",
+			"api_keys",
+			"championship_members",
+			"organization_members",
+			"refresh_tokens",
+			"users",
+			"player_championships",
+			"matches",
+			"player_unavailabilities",
+			"players",
+			"championships",
+			"seasons",
+			"organizations",
+		)
+	},
+}
+
+type season struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"index"`
+	Name           string `gorm:"not null"`
+	Description    string
+	DropWorst      int `gorm:"default:0;not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+
+	Championships []championship `gorm:"foreignKey:SeasonID"`
+}
+
+type championship struct {
+	ID                       uint   `gorm:"primaryKey"`
+	OrganizationID           uint   `gorm:"index"`
+	Name                     string `gorm:"not null"`
+	Description              string
+	Status                   string  `gorm:"type:varchar(20);default:'draft';not null"`
+	RequireConfirmation      bool    `gorm:"default:false;not null"`
+	ConfirmationTimeoutHours int     `gorm:"default:48;not null"`
+	SeasonID                 *uint   `gorm:"index"`
+	SeasonWeight             float64 `gorm:"default:1;not null"`
+	SeedingMethod            string  `gorm:"type:varchar(20)"`
+	DrawSeed                 *int64
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+	DeletedAt                gorm.DeletedAt `gorm:"index"`
+
+	Players []player           `gorm:"many2many:player_championships;"`
+	Matches []match            `gorm:"foreignKey:ChampionshipID"`
+	Seeds   []championshipSeed `gorm:"foreignKey:ChampionshipID"`
+}
+
+type championshipSeed struct {
+	ID             uint `gorm:"primaryKey"`
+	ChampionshipID uint `gorm:"not null;uniqueIndex:idx_championship_seed"`
+	PlayerID       uint `gorm:"not null;uniqueIndex:idx_championship_seed"`
+	Seed           int  `gorm:"not null"`
+
+	Player *player `gorm:"foreignKey:PlayerID"`
+}
+
+type player struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"index"`
+	Name           string `gorm:"not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+
+	Championships []championship `gorm:"many2many:player_championships;"`
+}
+
+type playerUnavailability struct {
+	ID        uint   `gorm:"primaryKey"`
+	PlayerID  uint   `gorm:"not null;index"`
+	Kind      string `gorm:"type:varchar(20);not null"`
+	Weekday   *int   `gorm:"default:null"`
+	StartDate string `gorm:"type:varchar(10)"`
+	EndDate   string `gorm:"type:varchar(10)"`
+	Reason    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type match struct {
+	ID             uint       `gorm:"primaryKey"`
+	OrganizationID uint       `gorm:"index"`
+	ChampionshipID uint       `gorm:"not null;index"`
+	Player1        string     `gorm:"not null"`
+	Player2        string     `gorm:"not null"`
+	Game           string     `gorm:"not null"`
+	Status         string     `gorm:"type:varchar(20);default:'pending';not null"`
+	Winner         *string    `gorm:"default:null"`
+	Player1Score   int        `gorm:"default:0;not null"`
+	Player2Score   int        `gorm:"default:0;not null"`
+	ScheduledAt    *time.Time `gorm:"default:null"`
+	StartedAt      *time.Time `gorm:"default:null"`
+	FinishedAt     *time.Time `gorm:"default:null"`
+	ReportedBy     *string    `gorm:"default:null"`
+	ReportedAt     *time.Time `gorm:"default:null"`
+	DisputeReason  string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"`
+
+	Championship championship `gorm:"foreignKey:ChampionshipID"`
+}
+
+type user struct {
+	ID           uint   `gorm:"primaryKey"`
+	Email        string `gorm:"not null;uniqueIndex"`
+	PasswordHash string `gorm:"not null"`
+	PlayerID     *uint  `gorm:"default:null;index"`
+	IsAdmin      bool   `gorm:"default:false;not null"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	DeletedAt    gorm.DeletedAt `gorm:"index"`
+
+	Player *player `gorm:"foreignKey:PlayerID"`
+}
+
+type refreshToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	TokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	RevokedAt *time.Time `gorm:"default:null"`
+	CreatedAt time.Time
+}
+
+type championshipMember struct {
+	ID             uint   `gorm:"primaryKey"`
+	ChampionshipID uint   `gorm:"not null;uniqueIndex:idx_championship_member"`
+	UserID         uint   `gorm:"not null;uniqueIndex:idx_championship_member;index"`
+	Role           string `gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	User *user `gorm:"foreignKey:UserID"`
+}
+
+type apiKey struct {
+	ID              uint       `gorm:"primaryKey"`
+	ChampionshipID  uint       `gorm:"not null;index"`
+	Name            string     `gorm:"not null"`
+	Prefix          string     `gorm:"type:varchar(16);not null"`
+	KeyHash         string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scope           string     `gorm:"type:varchar(20);not null"`
+	CreatedByUserID uint       `gorm:"not null"`
+	LastUsedAt      *time.Time `gorm:"default:null"`
+	ExpiresAt       *time.Time `gorm:"default:null"`
+	RevokedAt       *time.Time `gorm:"default:null"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type organization struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+type organizationMember struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"not null;uniqueIndex:idx_organization_member"`
+	UserID         uint   `gorm:"not null;uniqueIndex:idx_organization_member;index"`
+	Role           string `gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	User         *user         `gorm:"foreignKey:UserID"`
+	Organization *organization `gorm:"foreignKey:OrganizationID"`
+}
+
+type record struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"not null;uniqueIndex:idx_record_kind"`
+	Kind           string `gorm:"type:varchar(40);not null;uniqueIndex:idx_record_kind"`
+	PlayerName     string `gorm:"not null"`
+	Value          int    `gorm:"not null"`
+	MatchID        uint   `gorm:"not null"`
+	SetAt          *time.Time
+	UpdatedAt      time.Time
+
+	Match *match `gorm:"foreignKey:MatchID"`
+}
+
+type achievement struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"not null;index"`
+	PlayerID       uint   `gorm:"not null;uniqueIndex:idx_player_badge"`
+	Badge          string `gorm:"type:varchar(40);not null;uniqueIndex:idx_player_badge"`
+	MatchID        uint   `gorm:"not null"`
+	ChampionshipID uint   `gorm:"not null"`
+	AwardedAt      time.Time
+	CreatedAt      time.Time
+
+	Match *match `gorm:"foreignKey:MatchID"`
+}
+
+// legacyMatch is the matches table before championships were required
+type legacyMatch struct {
+	ChampionshipID *uint
+}
+
+func (legacyMatch) TableName() string {
+	return "matches"
+}
+
+// convertLegacyData moves scores and matches from before championships
+// existed into a default championship. Scores become players.
+func convertLegacyData(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	legacyMatches := migrator.HasTable("matches") && !migrator.HasColumn(&match{}, "championship_id")
+	legacyScores := migrator.HasTable("scores")
+	legacyPlayers := migrator.HasColumn(&player{}, "championship_id")
+	if !legacyMatches && !legacyScores && !legacyPlayers {
+		return nil
+	}
+
+	var defaultChampionship championship
+	err := tx.Where("name = ?", "Default Championship").First(&defaultChampionship).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaultChampionship = championship{
+			Name:        "Default Championship",
+			Description: "Default championship for existing data",
+			Status:      "draft",
+		}
+		err = tx.Create(&defaultChampionship).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create default championship: %w", err)
+	}
+
+	if legacyMatches {
+		// Nullable until the existing matches are assigned
+		if err := migrator.AddColumn(&legacyMatch{}, "ChampionshipID"); err != nil {
+			return fmt.Errorf("failed to add championship_id to matches: %w", err)
+		}
+		if err := tx.Exec("UPDATE matches SET championship_id = ? WHERE championship_id IS NULL", defaultChampionship.ID).Error; err != nil {
+			return fmt.Errorf("failed to update existing matches: %w", err)
+		}
+	}
+
+	join := func(playerID uint, championshipID uint) error {
+		return tx.Table("player_championships").Clauses(clause.OnConflict{DoNothing: true}).Create(map[string]interface{}{
+			"player_id":       playerID,
+			"championship_id": championshipID,
+		}).Error
+	}
+
+	if legacyScores {
+		// Every player that has a score becomes a player of its championship
+		var scores []struct {
+			Player         string
+			ChampionshipID uint
+		}
+		if err := tx.Table("scores").
+			Select("DISTINCT player, COALESCE(championship_id, ?) AS championship_id", defaultChampionship.ID).
+			Scan(&scores).Error; err != nil {
+			return fmt.Errorf("failed to read scores: %w", err)
+		}
+
+		for _, score := range scores {
+			var existing player
+			err := tx.Where("name = ?", score.Player).First(&existing).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				existing = player{Name: score.Player}
+				err = tx.Create(&existing).Error
+			}
+			if err != nil {
+				return fmt.Errorf("failed to create player %s: %w", score.Player, err)
+			}
+
+			var count int64
+			if err := tx.Model(&championship{}).Where("id = ?", score.ChampionshipID).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check championship %d: %w", score.ChampionshipID, err)
+			}
+			if count > 0 {
+				if err := join(existing.ID, score.ChampionshipID); err != nil {
+					return fmt.Errorf("failed to add player %s to championship: %w", score.Player, err)
+				}
+			}
+		}
+	}
+
+	if legacyPlayers {
+		// Players used to belong to a single championship
+		var players []struct {
+			ID             uint
+			ChampionshipID uint
+		}
+		if err := tx.Table("players").
+			Select("players.id, players.championship_id").
+			Joins("JOIN championships ON championships.id = players.championship_id").
+			Scan(&players).Error; err != nil {
+			return fmt.Errorf("failed to read player championships: %w", err)
+		}
+
+		for _, p := range players {
+			if err := join(p.ID, p.ChampionshipID); err != nil {
+				return fmt.Errorf("failed to add player %d to championship: %w", p.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// alignMatchColumns makes the winner column nullable and gives status its
+// default, for tables created before the model declared them
+func alignMatchColumns(tx *gorm.DB) error {
+	columns, err := tx.Migrator().ColumnTypes(&match{})
+	if err != nil {
+		return fmt.Errorf("failed to inspect matches: %w", err)
+	}
+
+	for _, column := range columns {
+		var outdated bool
+		switch column.Name() {
+		case "winner":
+			nullable, ok := column.Nullable()
+			outdated = ok && !nullable
+		case "status":
+			_, ok := column.DefaultValue()
+			outdated = !ok
+		}
+
+		if outdated {
+			if err := tx.Migrator().AlterColumn(&match{}, column.Name()); err != nil {
+				return fmt.Errorf("failed to alter matches.%s: %w", column.Name(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// adoptIntoDefaultOrganization moves data created before organizations existed
+// into a default organization and makes all existing users members of it
+func adoptIntoDefaultOrganization(tx *gorm.DB) error {
+	var orphanCount int64
+	for _, table := range []string{"championships", "players", "matches"} {
+		var count int64
+		if err := tx.Table(table).Where("organization_id IS NULL OR organization_id = 0").Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check %s for organization: %w", table, err)
+		}
+		orphanCount += count
+	}
+
+	var organizationCount int64
+	if err := tx.Model(&organization{}).Count(&organizationCount).Error; err != nil {
+		return fmt.Errorf("failed to count organizations: %w", err)
+	}
+
+	var userCount int64
+	if err := tx.Model(&user{}).Count(&userCount).Error; err != nil {
+		return fmt.Errorf("failed to count users: %w", err)
+	}
+
+	// Nothing to adopt, or users already set up organizations themselves
+	if orphanCount == 0 && (organizationCount > 0 || userCount == 0) {
+		return nil
+	}
+
+	var defaultOrganization organization
+	err := tx.Where("name = ?", "Default Organization").First(&defaultOrganization).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		defaultOrganization = organization{Name: "Default Organization"}
+		err = tx.Create(&defaultOrganization).Error
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create default organization: %w", err)
+	}
+
+	for _, table := range []string{"championships", "players", "matches"} {
+		if err := tx.Table(table).Where("organization_id IS NULL OR organization_id = 0").
+			Update("organization_id", defaultOrganization.ID).Error; err != nil {
+			return fmt.Errorf("failed to assign %s to default organization: %w", table, err)
+		}
+	}
+
+	// Users without any organization join the default one; admins as owners
+	var users []user
+	if err := tx.Where("id NOT IN (?)", tx.Model(&organizationMember{}).Select("user_id")).
+		Find(&users).Error; err != nil {
+		return fmt.Errorf("failed to fetch users without organization: %w", err)
+	}
+	for _, u := range users {
+		role := "member"
+		if u.IsAdmin {
+			role = "owner"
+		}
+		if err := tx.Create(&organizationMember{
+			OrganizationID: defaultOrganization.ID,
+			UserID:         u.ID,
+			Role:           role,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to add user to default organization: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+// backfillRecords computed the records of organizations with matches from
+// before records were introduced. It used the live records package, so it
+// would have changed with it; the backfill is now the backfill-records
+// command of cmd/migrate. The version is kept so databases that applied it
+// keep their history.
+var backfillRecords = Migration{
+	Version: 2,
+	Name:    "backfill_records",
+	Up: func(tx *gorm.DB) error {
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return nil
+	},
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// versioned is a frozen copy of the version column added to every table
+// whose rows clients edit concurrently
+type versioned struct {
+	Version uint `gorm:"default:1;not null"`
+}
+
+// versionedTables lists the tables that got a version column
+var versionedTables = []string{"seasons", "championships", "players", "matches"}
+
+// addVersions adds the version used for optimistic concurrency control.
+// Existing rows start at version 1.
+var addVersions = Migration{
+	Version: 3,
+	Name:    "add_versions",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range versionedTables {
+			migrator := tx.Table(table).Migrator()
+			if migrator.HasColumn(&versioned{}, "Version") {
+				continue
+			}
+			if err := migrator.AddColumn(&versioned{}, "Version"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, table := range versionedTables {
+			if err := tx.Table(table).Migrator().DropColumn(&versioned{}, "Version"); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
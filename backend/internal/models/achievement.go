@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type Badge string
+
+const (
+	BadgeFirstWin          Badge = "first_win"
+	BadgeWinStreak10       Badge = "win_streak_10"
+	BadgePerfectRoundRobin Badge = "perfect_round_robin"
+	BadgeGiantKiller       Badge = "giant_killer"
+)
+
+// Achievement is a badge a player earned. Every badge is awarded once per
+// player, together with the match that triggered it.
+type Achievement struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	OrganizationID uint      `json:"organization_id" gorm:"not null;index"`
+	PlayerID       uint      `json:"player_id" gorm:"not null;uniqueIndex:idx_player_badge"`
+	Badge          Badge     `json:"badge" gorm:"type:varchar(40);not null;uniqueIndex:idx_player_badge"`
+	MatchID        uint      `json:"match_id" gorm:"not null"`
+	ChampionshipID uint      `json:"championship_id" gorm:"not null"`
+	AwardedAt      time.Time `json:"awarded_at"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relations
+	Match *Match `json:"match,omitempty" gorm:"foreignKey:MatchID"`
+}
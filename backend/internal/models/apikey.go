@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+)
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead       APIKeyScope = "read"
+	APIKeyScopeScoreEntry APIKeyScope = "score_entry"
+)
+
+// Role returns the championship role an API key with this scope acts as
+func (s APIKeyScope) Role() ChampionshipRole {
+	switch s {
+	case APIKeyScopeRead:
+		return ChampionshipRoleViewer
+	case APIKeyScopeScoreEntry:
+		return ChampionshipRoleReferee
+	}
+	return ""
+}
+
+// APIKey grants machine clients scoped access to one championship. Only the
+// SHA-256 hash of the key is stored; Prefix helps users recognize their keys.
+type APIKey struct {
+	ID              uint        `json:"id" gorm:"primaryKey"`
+	ChampionshipID  uint        `json:"championship_id" gorm:"not null;index"`
+	Name            string      `json:"name" gorm:"not null"`
+	Prefix          string      `json:"prefix" gorm:"type:varchar(16);not null"`
+	KeyHash         string      `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	Scope           APIKeyScope `json:"scope" gorm:"type:varchar(20);not null"`
+	CreatedByUserID uint        `json:"created_by_user_id" gorm:"not null"`
+	LastUsedAt      *time.Time  `json:"last_used_at" gorm:"default:null"`
+	ExpiresAt       *time.Time  `json:"expires_at" gorm:"default:null"`
+	RevokedAt       *time.Time  `json:"revoked_at" gorm:"default:null"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UnavailabilityKind string
+
+const (
+	UnavailabilityKindWeekday   UnavailabilityKind = "weekday"
+	UnavailabilityKindDateRange UnavailabilityKind = "date_range"
+)
+
+// DateLayout is the format used for calendar dates such as vacation ranges
+const DateLayout = "2006-01-02"
+
+type PlayerUnavailability struct {
+	ID        uint               `json:"id" gorm:"primaryKey"`
+	PlayerID  uint               `json:"player_id" gorm:"not null;index"`
+	Kind      UnavailabilityKind `json:"kind" gorm:"type:varchar(20);not null"`
+	Weekday   *int               `json:"weekday,omitempty" gorm:"default:null"`        // 0 = Sunday ... 6 = Saturday
+	StartDate string             `json:"start_date,omitempty" gorm:"type:varchar(10)"` // YYYY-MM-DD, inclusive
+	EndDate   string             `json:"end_date,omitempty" gorm:"type:varchar(10)"`   // YYYY-MM-DD, inclusive
+	Reason    string             `json:"reason"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	DeletedAt gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+// Blocks reports whether the player is unavailable at the given time.
+// Weekdays and dates are evaluated in the time's own location.
+func (u PlayerUnavailability) Blocks(t time.Time) bool {
+	switch u.Kind {
+	case UnavailabilityKindWeekday:
+		return u.Weekday != nil && int(t.Weekday()) == *u.Weekday
+	case UnavailabilityKindDateRange:
+		date := t.Format(DateLayout)
+		return date >= u.StartDate && date <= u.EndDate
+	}
+	return false
+}
+
+func (u PlayerUnavailability) Describe() string {
+	var description string
+	switch u.Kind {
+	case UnavailabilityKindWeekday:
+		if u.Weekday != nil {
+			description = "not on " + time.Weekday(*u.Weekday).String() + "s"
+		}
+	case UnavailabilityKindDateRange:
+		description = "unavailable from " + u.StartDate + " to " + u.EndDate
+	}
+	if u.Reason != "" {
+		description += " (" + u.Reason + ")"
+	}
+	return description
+}
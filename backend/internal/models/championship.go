@@ -15,15 +15,39 @@ const (
 
 type Championship struct {
 	ID          uint               `json:"id" gorm:"primaryKey"`
+	OrganizationID uint            `json:"organization_id" gorm:"index"`
 	Name        string             `json:"name" gorm:"not null"`
 	Description string             `json:"description"`
 	Status      ChampionshipStatus `json:"status" gorm:"type:varchar(20);default:'draft';not null"`
+	// Results reported by players must be confirmed by the opponent
+	RequireConfirmation      bool `json:"require_confirmation" gorm:"default:false;not null"`
+	ConfirmationTimeoutHours int  `json:"confirmation_timeout_hours" gorm:"default:48;not null"`
+	// Season the championship counts towards, and the factor its points are multiplied with
+	SeasonID     *uint   `json:"season_id" gorm:"index"`
+	SeasonWeight float64 `json:"season_weight" gorm:"default:1;not null"`
+	// How the players were seeded when the matches were generated
+	SeedingMethod string `json:"seeding_method,omitempty" gorm:"type:varchar(20)"`
+	DrawSeed      *int64 `json:"draw_seed,omitempty"`
+	// Incremented on every change and sent as the ETag
+	Version     uint               `json:"version" gorm:"default:1;not null"`
 	CreatedAt   time.Time          `json:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt     `json:"-" gorm:"index"`
 	
 	// Relations
-	Players []Player `json:"players,omitempty" gorm:"many2many:player_championships;"`
-	Matches []Match  `json:"matches,omitempty" gorm:"foreignKey:ChampionshipID"`
+	Players []Player           `json:"players,omitempty" gorm:"many2many:player_championships;"`
+	Matches []Match            `json:"matches,omitempty" gorm:"foreignKey:ChampionshipID"`
+	Seeds   []ChampionshipSeed `json:"seeds,omitempty" gorm:"foreignKey:ChampionshipID"`
+}
+
+// ChampionshipSeed is the position a player was drawn at
+type ChampionshipSeed struct {
+	ID             uint `json:"id" gorm:"primaryKey"`
+	ChampionshipID uint `json:"championship_id" gorm:"not null;uniqueIndex:idx_championship_seed"`
+	PlayerID       uint `json:"player_id" gorm:"not null;uniqueIndex:idx_championship_seed"`
+	Seed           int  `json:"seed" gorm:"not null"`
+
+	// Relations
+	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
 }
 
@@ -12,10 +12,14 @@ const (
 	MatchStatusPending  MatchStatus = "pending"
 	MatchStatusStarted  MatchStatus = "started"
 	MatchStatusFinished MatchStatus = "finished"
+	// Self-reported results wait for the opponent before they become official
+	MatchStatusPendingConfirmation MatchStatus = "pending_confirmation"
+	MatchStatusDisputed            MatchStatus = "disputed"
 )
 
 type Match struct {
 	ID            uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint          `json:"organization_id" gorm:"index"` // Copied from the championship for tenant scoping
 	ChampionshipID uint          `json:"championship_id" gorm:"not null;index"`
 	Player1       string         `json:"player1" gorm:"not null"`
 	Player2       string         `json:"player2" gorm:"not null"`
@@ -24,8 +28,13 @@ type Match struct {
 	Winner        *string        `json:"winner" gorm:"default:null"` // Nullable, wird erst beim Beenden gesetzt
 	Player1Score  int            `json:"player1_score" gorm:"default:0;not null"`
 	Player2Score  int            `json:"player2_score" gorm:"default:0;not null"`
+	ScheduledAt   *time.Time     `json:"scheduled_at" gorm:"default:null"`
 	StartedAt     *time.Time     `json:"started_at" gorm:"default:null"`
 	FinishedAt    *time.Time     `json:"finished_at" gorm:"default:null"`
+	ReportedBy    *string        `json:"reported_by" gorm:"default:null"` // Player who proposed the result
+	ReportedAt    *time.Time     `json:"reported_at" gorm:"default:null"`
+	DisputeReason string         `json:"dispute_reason,omitempty"`
+	Version       uint           `json:"version" gorm:"default:1;not null"` // Incremented on every change
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
@@ -34,3 +43,17 @@ type Match struct {
 	Championship Championship `json:"championship,omitempty" gorm:"foreignKey:ChampionshipID"`
 }
 
+// Finish makes the current score official and determines the winner
+func (m *Match) Finish(now time.Time) {
+	// Determine winner based on score
+	var winner *string
+	if m.Player1Score > m.Player2Score {
+		winner = &m.Player1
+	} else if m.Player2Score > m.Player1Score {
+		winner = &m.Player2
+	}
+
+	m.Status = MatchStatusFinished
+	m.Winner = winner
+	m.FinishedAt = &now
+}
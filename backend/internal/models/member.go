@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+)
+
+type ChampionshipRole string
+
+const (
+	ChampionshipRoleOwner     ChampionshipRole = "owner"
+	ChampionshipRoleOrganizer ChampionshipRole = "organizer"
+	ChampionshipRoleReferee   ChampionshipRole = "referee"
+	ChampionshipRolePlayer    ChampionshipRole = "player"
+	ChampionshipRoleViewer    ChampionshipRole = "viewer"
+)
+
+var championshipRoleRanks = map[ChampionshipRole]int{
+	ChampionshipRoleViewer:    1,
+	ChampionshipRolePlayer:    2,
+	ChampionshipRoleReferee:   3,
+	ChampionshipRoleOrganizer: 4,
+	ChampionshipRoleOwner:     5,
+}
+
+func (r ChampionshipRole) Valid() bool {
+	_, ok := championshipRoleRanks[r]
+	return ok
+}
+
+// AtLeast reports whether r grants everything min grants. The empty role grants nothing.
+func (r ChampionshipRole) AtLeast(min ChampionshipRole) bool {
+	return r.Valid() && championshipRoleRanks[r] >= championshipRoleRanks[min]
+}
+
+type ChampionshipMember struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	ChampionshipID uint             `json:"championship_id" gorm:"not null;uniqueIndex:idx_championship_member"`
+	UserID         uint             `json:"user_id" gorm:"not null;uniqueIndex:idx_championship_member;index"`
+	Role           ChampionshipRole `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRole string
+
+const (
+	OrganizationRoleOwner  OrganizationRole = "owner"
+	OrganizationRoleAdmin  OrganizationRole = "admin"
+	OrganizationRoleMember OrganizationRole = "member"
+)
+
+var organizationRoleRanks = map[OrganizationRole]int{
+	OrganizationRoleMember: 1,
+	OrganizationRoleAdmin:  2,
+	OrganizationRoleOwner:  3,
+}
+
+func (r OrganizationRole) Valid() bool {
+	_, ok := organizationRoleRanks[r]
+	return ok
+}
+
+// AtLeast reports whether r grants everything min grants. The empty role grants nothing.
+func (r OrganizationRole) AtLeast(min OrganizationRole) bool {
+	return r.Valid() && organizationRoleRanks[r] >= organizationRoleRanks[min]
+}
+
+// Organization is a tenant that owns championships, players and their matches
+type Organization struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type OrganizationMember struct {
+	ID             uint             `json:"id" gorm:"primaryKey"`
+	OrganizationID uint             `json:"organization_id" gorm:"not null;uniqueIndex:idx_organization_member"`
+	UserID         uint             `json:"user_id" gorm:"not null;uniqueIndex:idx_organization_member;index"`
+	Role           OrganizationRole `json:"role" gorm:"type:varchar(20);not null"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	// Relations
+	User         *User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Organization *Organization `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+}
@@ -8,7 +8,9 @@ import (
 
 type Player struct {
 	ID            uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint          `json:"organization_id" gorm:"index"`
 	Name          string         `json:"name" gorm:"not null"`
+	Version       uint           `json:"version" gorm:"default:1;not null"` // Incremented on every change
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
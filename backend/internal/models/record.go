@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type RecordKind string
+
+const (
+	RecordMostChampionshipsWon RecordKind = "most_championships_won"
+	RecordHighestScore         RecordKind = "highest_score"
+	RecordLongestUnbeatenRun   RecordKind = "longest_unbeaten_run"
+	RecordBiggestMargin        RecordKind = "biggest_margin"
+	RecordMostMatchesPlayed    RecordKind = "most_matches_played"
+)
+
+// RecordKinds lists all records in display order
+var RecordKinds = []RecordKind{
+	RecordMostChampionshipsWon,
+	RecordHighestScore,
+	RecordLongestUnbeatenRun,
+	RecordBiggestMargin,
+	RecordMostMatchesPlayed,
+}
+
+// Record is an all-time best of an organization. MatchID points to the match
+// that set it; records are only taken over by strictly better values.
+type Record struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	OrganizationID uint       `json:"organization_id" gorm:"not null;uniqueIndex:idx_record_kind"`
+	Kind           RecordKind `json:"kind" gorm:"type:varchar(40);not null;uniqueIndex:idx_record_kind"`
+	PlayerName     string     `json:"player_name" gorm:"not null"`
+	Value          int        `json:"value" gorm:"not null"`
+	MatchID        uint       `json:"match_id" gorm:"not null"`
+	SetAt          *time.Time `json:"set_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	// Relations
+	Match *Match `json:"match,omitempty" gorm:"foreignKey:MatchID"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Season groups championships whose results add up to season standings
+type Season struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	OrganizationID uint   `json:"organization_id" gorm:"index"`
+	Name           string `json:"name" gorm:"not null"`
+	Description    string `json:"description"`
+	// Number of each player's worst championship results that do not count
+	DropWorst int `json:"drop_worst" gorm:"default:0;not null"`
+	// Incremented on every change
+	Version   uint           `json:"version" gorm:"default:1;not null"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Championships []Championship `json:"championships,omitempty" gorm:"foreignKey:SeasonID"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type User struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	Email        string         `json:"email" gorm:"not null;uniqueIndex"`
+	PasswordHash string         `json:"-" gorm:"not null"`
+	PlayerID     *uint          `json:"player_id" gorm:"default:null;index"`    // Optional link to the player this user plays as
+	IsAdmin      bool           `json:"is_admin" gorm:"default:false;not null"` // First user before organizations, grants no access by itself
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relations
+	Player *Player `json:"player,omitempty" gorm:"foreignKey:PlayerID"`
+}
+
+// RefreshToken stores only the SHA-256 hash of the token handed to the client
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	TokenHash string     `json:"-" gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt *time.Time `json:"revoked_at" gorm:"default:null"`
+	CreatedAt time.Time  `json:"created_at"`
+}
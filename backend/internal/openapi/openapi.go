@@ -0,0 +1,292 @@
+// Package openapi describes the HTTP API as an OpenAPI 3 document per API
+// version. Schemas are derived from the models and request types. Tests
+// compare the documented Routes with the routes the server registers, see
+// the openapitest package.
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/service"
+)
+
+// BasePath is the prefix of all routes. The routes of version 1 are also
+// served directly under it for clients from before versioning.
+const BasePath = "/api"
+
+// Versions of the API. All but the latest are deprecated.
+var Versions = []int{1, 2}
+
+// Latest is the version new clients should use
+const Latest = 2
+
+// Prefix returns the path prefix of the routes of a version
+func Prefix(version int) string {
+	return fmt.Sprintf("%s/v%d", BasePath, version)
+}
+
+// DocsPage renders the document in a browser
+//
+//go:embed docs.html
+var DocsPage []byte
+
+var (
+	documents     = map[int]*Document{}
+	documentsOnce sync.Once
+)
+
+// Spec returns the document of a version of the API
+func Spec(version int) *Document {
+	documentsOnce.Do(func() {
+		for _, v := range Versions {
+			documents[v] = build(v)
+		}
+	})
+	return documents[version]
+}
+
+// Route is a documented endpoint under one of the prefixes it is served at
+type Route struct {
+	Method string
+	// Gin syntax, including the prefix
+	Path   string
+	Access Access
+}
+
+// Routes lists the documented endpoints under the prefix of every version,
+// and those of version 1 also directly under BasePath
+func Routes() []Route {
+	prefixes := []string{BasePath}
+	for _, version := range Versions {
+		prefixes = append(prefixes, Prefix(version))
+	}
+
+	var routes []Route
+	for _, prefix := range prefixes {
+		for _, e := range endpoints {
+			routes = append(routes, Route{Method: e.method, Path: prefix + e.path, Access: e.access})
+		}
+	}
+	return routes
+}
+
+var routeParam = regexp.MustCompile(`:(\w+)`)
+
+func build(version int) *Document {
+	s := newSchemas()
+	enums(s)
+	s.of(reflect.TypeOf(problem.Problem{}))
+	s.components["Problem"].Required = []string{"type", "title", "status", "detail", "code"}
+	s.representations = representations[version]
+
+	description := "Championships, players and matches of the caller's organization. " +
+		"Users with several organizations select one with the X-Organization-ID header."
+	if version < Latest {
+		description += fmt.Sprintf(" This version is deprecated, responses link to the same route of version %d.", Latest)
+	}
+	servers := []Server{{URL: Prefix(version)}}
+	if version == 1 {
+		servers = append(servers, Server{URL: BasePath, Description: "Unversioned alias of version 1"})
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:       "Score Tracker API",
+			Version:     fmt.Sprintf("%d.0.0", version),
+			Description: description,
+		},
+		Servers: servers,
+		Paths:   map[string]*PathItem{},
+		Components: Components{
+			Schemas: s.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearer": {
+					Type:        "http",
+					Scheme:      "bearer",
+					Description: "Access token from /auth/login, or an API key where API keys are accepted",
+				},
+				"apiKeyQuery": {
+					Type:        "apiKey",
+					In:          "query",
+					Name:        "api_key",
+					Description: "API key for calendar feeds, whose clients cannot send headers",
+				},
+			},
+		},
+	}
+
+	var tags []string
+	for _, e := range endpoints {
+		path := routeParam.ReplaceAllString(e.path, "{$1}")
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[path] = item
+		}
+		(*item)[strings.ToLower(e.method)] = operation(s, e, version)
+
+		if !slices.Contains(tags, e.tag) {
+			tags = append(tags, e.tag)
+		}
+	}
+	for _, tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+
+	return doc
+}
+
+func operation(s *schemas, e endpoint, version int) *Operation {
+	op := &Operation{
+		Tags:        []string{e.tag},
+		Summary:     e.summary,
+		OperationID: e.id,
+		Responses:   map[string]*Response{},
+		Deprecated:  version < Latest,
+	}
+
+	request, response := e.request, e.response
+	if version >= 2 && e.v2Request != nil {
+		request = e.v2Request
+	}
+
+	for _, match := range routeParam.FindAllStringSubmatch(e.path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: integer()})
+	}
+	op.Parameters = append(op.Parameters, e.query...)
+
+	if e.sorting != nil {
+		defaultPageSize := service.DefaultPageSize
+		if version == 1 {
+			defaultPageSize = service.MaxPageSize
+		}
+		op.Parameters = append(op.Parameters,
+			queryParam("limit", fmt.Sprintf("Rows per page, at most %d. Defaults to %d.", service.MaxPageSize, defaultPageSize), integer()),
+			queryParam("cursor", "Cursor of the next page, from the Link header", stringSchema()),
+			queryParam("sort", fmt.Sprintf("Field to sort by, prefixed with - for descending order. Defaults to %s.", e.sorting.Default),
+				&Schema{Type: "string", Enum: sortValues(e.sorting.Fields)}),
+		)
+	}
+
+	switch e.access {
+	case AccessAccount:
+		op.Security = []map[string][]string{{"bearer": {}}}
+	case AccessTenant, AccessUsers, AccessFeed:
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        "X-Organization-ID",
+			In:          "header",
+			Description: "Organization to act in, required for users of several organizations",
+			Schema:      integer(),
+		})
+		op.Security = []map[string][]string{{"bearer": {}}}
+		switch e.access {
+		case AccessTenant:
+			op.Description = "Accepts API keys, which only read their own championship."
+		case AccessFeed:
+			op.Security = append(op.Security, map[string][]string{"apiKeyQuery": {}})
+			op.Description = "Accepts API keys, which only read their own championship, also as the api_key query parameter."
+		}
+	}
+
+	if e.ifMatch {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:        "If-Match",
+			In:          "header",
+			Description: "ETag of the version the change is based on",
+			Schema:      stringSchema(),
+		})
+	}
+
+	if request != nil {
+		op.RequestBody = &RequestBody{
+			Required: !e.optionalBody,
+			Content:  map[string]*MediaType{"application/json": {Schema: s.of(reflect.TypeOf(request))}},
+		}
+	}
+
+	status := e.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case e.contentType != "":
+		success.Content = map[string]*MediaType{e.contentType: {Schema: stringSchema()}}
+	case response != nil:
+		success.Content = map[string]*MediaType{"application/json": {Schema: s.of(reflect.TypeOf(response))}}
+	}
+	headers := map[string]*Header{}
+	if e.etag {
+		headers["ETag"] = &Header{Description: "Version of the record", Schema: stringSchema()}
+	}
+	if e.sorting != nil {
+		headers["X-Total-Count"] = &Header{Description: "Number of rows on all pages", Schema: integer()}
+		headers["Link"] = &Header{Description: `Link to the next page with rel="next", missing on the last page`, Schema: stringSchema()}
+	}
+	if op.Deprecated {
+		headers["Deprecation"] = &Header{Description: "Time the version was deprecated, as @ followed by Unix seconds", Schema: stringSchema()}
+		headers["Sunset"] = &Header{Description: "Time the version stops working, once it is planned", Schema: stringSchema()}
+		link := `Link to the same route of the latest version with rel="successor-version"`
+		if e.sorting != nil {
+			link = `Link to the next page with rel="next", missing on the last page, and ` + strings.TrimPrefix(link, "Link ")
+		}
+		headers["Link"] = &Header{Description: link, Schema: stringSchema()}
+	}
+	if len(headers) > 0 {
+		success.Headers = headers
+	}
+	op.Responses[strconv.Itoa(status)] = success
+
+	errorBody := map[string]*MediaType{problem.ContentType: {Schema: s.of(reflect.TypeOf(problem.Problem{}))}}
+	failures := map[int]*Response{}
+	if e.access != AccessPublic {
+		failures[http.StatusUnauthorized] = &Response{Content: errorBody}
+		failures[http.StatusForbidden] = &Response{Content: errorBody}
+	}
+	if strings.Contains(e.path, ":") {
+		failures[http.StatusNotFound] = &Response{Content: errorBody}
+	}
+	if e.ifMatch {
+		failures[http.StatusPreconditionFailed] = &Response{Content: errorBody}
+	}
+	for status, body := range e.failures {
+		failures[status] = &Response{Content: map[string]*MediaType{problem.ContentType: {Schema: s.of(reflect.TypeOf(body))}}}
+	}
+	for status, response := range failures {
+		response.Description = http.StatusText(status)
+		op.Responses[strconv.Itoa(status)] = response
+	}
+	op.Responses["default"] = &Response{Description: "Error", Content: errorBody}
+
+	return op
+}
+
+func queryParam(name string, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func stringSchema() *Schema {
+	return &Schema{Type: "string"}
+}
+
+func integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+func sortValues(fields []string) []string {
+	values := make([]string, 0, 2*len(fields))
+	for _, field := range fields {
+		values = append(values, field, "-"+field)
+	}
+	return values
+}
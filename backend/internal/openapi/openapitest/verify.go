@@ -0,0 +1,133 @@
+// Package openapitest checks the routes of a server against the OpenAPI
+// specification. Only tests import it, so it stays out of the server binary.
+package openapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"scoretracker/backend/internal/openapi"
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Credentials of one organization, with which Verify calls every route as
+// each kind of caller
+type Credentials struct {
+	// Access token of a user
+	UserToken string
+	// API key of a championship of the organization
+	APIKey string
+}
+
+var routeParam = regexp.MustCompile(`:(\w+)`)
+
+// Verify compares the documented endpoints with the routes of a router and
+// fails if any route is undocumented or any endpoint is missing under the
+// prefix of a version. It then calls every route anonymously, with the user
+// token and with the API key, and fails if a route accepts other callers than
+// its documented access allows.
+func Verify(router *gin.Engine, credentials Credentials) error {
+	documented := map[string]bool{}
+	for _, route := range openapi.Routes() {
+		documented[route.Method+" "+route.Path] = true
+	}
+
+	registered := map[string]bool{}
+	var undocumented []string
+	for _, route := range router.Routes() {
+		key := route.Method + " " + route.Path
+		registered[key] = true
+		if !documented[key] {
+			undocumented = append(undocumented, key)
+		}
+	}
+
+	var unrouted, misrouted []string
+	for _, route := range openapi.Routes() {
+		key := route.Method + " " + route.Path
+		if !registered[key] {
+			unrouted = append(unrouted, key)
+			continue
+		}
+		if mismatch := checkAccess(router, credentials, route); mismatch != "" {
+			misrouted = append(misrouted, key+" "+mismatch)
+		}
+	}
+
+	if len(undocumented) == 0 && len(unrouted) == 0 && len(misrouted) == 0 {
+		return nil
+	}
+
+	slices.Sort(undocumented)
+	slices.Sort(unrouted)
+	var problems []string
+	if len(undocumented) > 0 {
+		problems = append(problems, "routes missing from the specification: "+strings.Join(undocumented, ", "))
+	}
+	if len(unrouted) > 0 {
+		problems = append(problems, "documented endpoints without a route: "+strings.Join(unrouted, ", "))
+	}
+	if len(misrouted) > 0 {
+		problems = append(problems, "routes with other access than documented: "+strings.Join(misrouted, ", "))
+	}
+	return fmt.Errorf("OpenAPI specification is out of date, %s", strings.Join(problems, "; "))
+}
+
+// checkAccess calls a route as every kind of caller and describes how it
+// differs from the documented access, empty if it does not. Each caller is
+// told apart by the problem the middleware answers with, so the handlers are
+// never reached with a body.
+func checkAccess(router *gin.Engine, credentials Credentials, route openapi.Route) string {
+	path := routeParam.ReplaceAllString(route.Path, "1")
+	call := func(path string, header map[string]string) (int, string) {
+		request := httptest.NewRequest(route.Method, path, nil)
+		for name, value := range header {
+			request.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		var body problem.Problem
+		_ = json.Unmarshal(recorder.Body.Bytes(), &body)
+		return recorder.Code, body.Code
+	}
+
+	status, _ := call(path, nil)
+	if (status == http.StatusUnauthorized) != (route.Access != openapi.AccessPublic) {
+		return fmt.Sprintf("answers anonymous callers with %d", status)
+	}
+	if route.Access == openapi.AccessPublic {
+		return ""
+	}
+
+	// An invalid organization is only rejected by routes scoped to one
+	_, code := call(path, map[string]string{
+		"Authorization":     "Bearer " + credentials.UserToken,
+		"X-Organization-ID": "invalid",
+	})
+	if scoped := code == problem.CodeInvalidID; scoped != (route.Access != openapi.AccessAccount) {
+		return "is scoped to an organization differently"
+	}
+
+	_, code = call(path, map[string]string{"Authorization": "Bearer " + credentials.APIKey})
+	if rejected := code == problem.CodeUserRequired; rejected != (route.Access == openapi.AccessAccount || route.Access == openapi.AccessUsers) {
+		return "treats API keys differently"
+	}
+
+	if route.Method == http.MethodGet {
+		status, _ = call(path+"?"+url.Values{"api_key": {credentials.APIKey}}.Encode(), nil)
+		if accepted := status != http.StatusUnauthorized; accepted != (route.Access == openapi.AccessFeed) {
+			return "treats API keys in the query differently"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,210 @@
+package openapi
+
+import (
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemas derives schemas from the Go types the handlers bind and respond
+// with, following their json tags. Named structs and enums become components.
+type schemas struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+	enums      map[reflect.Type][]string
+	// Types sent as other types in the version being described
+	representations map[reflect.Type]reflect.Type
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: map[string]*Schema{},
+		names:      map[reflect.Type]string{},
+		enums:      map[reflect.Type][]string{},
+	}
+}
+
+// enum registers the values of a string type
+func enum[T ~string](s *schemas, values ...T) {
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		names = append(names, string(value))
+	}
+	s.enums[reflect.TypeOf(values[0])] = names
+}
+
+// of returns the schema of the JSON encoding of t
+func (s *schemas) of(t reflect.Type) *Schema {
+	if representation, ok := s.representations[t]; ok {
+		t = representation
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.of(t.Elem())
+		// Siblings of $ref are ignored, so nullable needs a wrapper
+		if schema.Ref != "" {
+			return &Schema{AllOf: []*Schema{schema}, Nullable: true}
+		}
+		schema.Nullable = true
+		return schema
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return s.component(t, func() *Schema { return s.object(t) })
+	case reflect.String:
+		if values, ok := s.enums[t]; ok {
+			return s.component(t, func() *Schema { return &Schema{Type: "string", Enum: values} })
+		}
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	// Interfaces may hold anything
+	return &Schema{}
+}
+
+// component returns a reference to the component of t, building it on first use
+func (s *schemas) component(t reflect.Type, build func() *Schema) *Schema {
+	name, ok := s.names[t]
+	if !ok {
+		name = exportedName(t.Name())
+		// Types of different packages may share a name, e.g. models.Record and stats.Record
+		if _, taken := s.components[name]; taken {
+			name = exportedName(path.Base(t.PkgPath())) + name
+		}
+		s.names[t] = name
+		// Registered before building, as models refer to each other
+		s.components[name] = &Schema{}
+		*s.components[name] = *build()
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object lists the fields of a struct as encoding/json does. Fields with
+// binding:"required" are required, and other binding rules become constraints.
+func (s *schemas) object(t reflect.Type) *Schema {
+	return s.fields(t, nil)
+}
+
+// fields builds the object of a struct without the shadowed fields, which the
+// struct embedding it replaces
+func (s *schemas) fields(t reflect.Type, shadowed map[string]bool) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	// Fields of embedded structs are shadowed by fields of the same name
+	own := map[string]bool{}
+	for name := range shadowed {
+		own[name] = true
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if tag == "-" || field.Anonymous && name == "" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		own[name] = true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		// Fields of embedded structs are promoted
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := s.fields(field.Type, own)
+			for property, propertySchema := range embedded.Properties {
+				schema.Properties[property] = propertySchema
+			}
+			schema.Required = append(schema.Required, embedded.Required...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if shadowed[name] {
+			continue
+		}
+		schema.Properties[name] = s.of(field.Type)
+		constrain(schema.Properties[name], field.Tag.Get("binding"))
+		if strings.Contains(field.Tag.Get("binding"), "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema
+}
+
+// constrain adds the binding rules of a field to its schema. Components
+// are shared, so references are left alone.
+func constrain(schema *Schema, rules string) {
+	if schema.Ref != "" || schema.AllOf != nil || rules == "" {
+		return
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+		number, err := strconv.ParseFloat(param, 64)
+		hasNumber := err == nil
+		length := int(number)
+
+		switch {
+		case (tag == "min" || tag == "gte") && hasNumber && schema.Type == "string":
+			schema.MinLength = &length
+		case (tag == "min" || tag == "gte") && hasNumber:
+			schema.Minimum = &number
+		case (tag == "max" || tag == "lte") && hasNumber && schema.Type == "string":
+			schema.MaxLength = &length
+		case (tag == "max" || tag == "lte") && hasNumber:
+			schema.Maximum = &number
+		case tag == "gt" && hasNumber:
+			schema.Minimum, schema.ExclusiveMinimum = &number, true
+		case tag == "oneof":
+			schema.Enum = strings.Fields(param)
+		case tag == "email":
+			schema.Format = "email"
+		}
+	}
+}
+
+func exportedName(name string) string {
+	runes := []rune(name)
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
@@ -0,0 +1,334 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/apiv2"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/projections"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/requests"
+	"scoretracker/backend/internal/scheduler"
+	"scoretracker/backend/internal/seeding"
+	"scoretracker/backend/internal/service"
+	"scoretracker/backend/internal/standings"
+	"scoretracker/backend/internal/stats"
+)
+
+// Access tells who may call an endpoint
+type Access int
+
+const (
+	AccessPublic Access = iota
+	// User JWTs, not tied to an organization
+	AccessAccount
+	// User JWTs and API keys, scoped to the caller's organization
+	AccessTenant
+	// User JWTs only, scoped to the caller's organization
+	AccessUsers
+	// Like AccessTenant, and GET requests may pass the API key as query parameter
+	AccessFeed
+)
+
+// endpoint describes a route. Request and response are values of the types
+// the handler binds and responds with, their schemas are derived from them.
+type endpoint struct {
+	method string
+	// Gin syntax, relative to /api
+	path    string
+	id      string
+	tag     string
+	summary string
+	access  Access
+	query   []Parameter
+	request interface{}
+	// The request of version 2 where it differs
+	v2Request interface{}
+	// The request body may be left out
+	optionalBody bool
+	status       int
+	response     interface{}
+	// Content type of responses that are not JSON
+	contentType string
+	// Lists accept limit, cursor and sort
+	sorting *repository.Sorting
+	// Accepts If-Match and sends the ETag of the record
+	ifMatch bool
+	etag    bool
+	// Error responses with a body other than Problem, by status
+	failures map[int]interface{}
+}
+
+// representations are the types the values handlers respond with are sent as
+// in each version, as converted by the adapters of the handlers
+var representations = map[int]map[reflect.Type]reflect.Type{
+	1: {
+		reflect.TypeOf(standings.Row{}): reflect.TypeOf(standings.Standing{}),
+	},
+	2: {
+		reflect.TypeOf(models.Match{}):              reflect.TypeOf(apiv2.Match{}),
+		reflect.TypeOf(models.Championship{}):       reflect.TypeOf(apiv2.Championship{}),
+		reflect.TypeOf(service.Draw{}):              reflect.TypeOf(apiv2.Draw{}),
+		reflect.TypeOf(models.Record{}):             reflect.TypeOf(apiv2.Record{}),
+		reflect.TypeOf(achievements.Earned{}):       reflect.TypeOf(apiv2.Achievement{}),
+		reflect.TypeOf(standings.Row{}):             reflect.TypeOf(apiv2.Standing{}),
+		reflect.TypeOf(service.StandingsSnapshot{}): reflect.TypeOf(apiv2.StandingsSnapshot{}),
+	},
+}
+
+type message struct {
+	Message string `json:"message"`
+}
+
+type tokens struct {
+	AccessToken      string    `json:"access_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+type generatedMatches struct {
+	Message string         `json:"message"`
+	Count   int            `json:"count"`
+	Matches []models.Match `json:"matches"`
+	Draw    service.Draw   `json:"draw"`
+}
+
+type scheduledMatches struct {
+	Message string         `json:"message"`
+	Count   int            `json:"count"`
+	Matches []models.Match `json:"matches"`
+}
+
+type scheduleConflicts struct {
+	problem.Problem
+	Conflicts []scheduler.Conflict `json:"conflicts"`
+}
+
+var endpoints = []endpoint{
+	{method: "GET", path: "/health", id: "getHealth", tag: "Meta", summary: "Health check", access: AccessPublic,
+		response: struct {
+			Status  string `json:"status"`
+			Service string `json:"service"`
+		}{}},
+	{method: "GET", path: "/openapi.json", id: "getOpenAPI", tag: "Meta", summary: "This OpenAPI document", access: AccessPublic,
+		response: map[string]interface{}{}},
+	{method: "GET", path: "/docs", id: "getDocs", tag: "Meta", summary: "Documentation of this API", access: AccessPublic,
+		contentType: "text/html"},
+
+	// Authentication
+	{method: "POST", path: "/auth/register", id: "register", tag: "Authentication", summary: "Register a user", access: AccessPublic,
+		request: requests.Register{}, status: 201, response: models.User{}},
+	{method: "POST", path: "/auth/login", id: "login", tag: "Authentication", summary: "Log in with email and password", access: AccessPublic,
+		request: requests.Login{}, response: tokens{}},
+	{method: "POST", path: "/auth/refresh", id: "refresh", tag: "Authentication", summary: "Exchange a refresh token for new tokens", access: AccessPublic,
+		request: requests.RefreshToken{}, response: tokens{}},
+	{method: "POST", path: "/auth/logout", id: "logout", tag: "Authentication", summary: "Revoke a refresh token", access: AccessPublic,
+		request: requests.RefreshToken{}, response: message{}},
+
+	// Account
+	{method: "GET", path: "/me", id: "getCurrentUser", tag: "Account", summary: "Current user", access: AccessAccount,
+		response: models.User{}},
+	{method: "PUT", path: "/me", id: "updateCurrentUser", tag: "Account", summary: "Unlink the player the current user plays as, admins may also link one", access: AccessAccount,
+		request: requests.UpdateUser{}, response: models.User{}},
+	{method: "GET", path: "/me/matches", id: "getMyMatches", tag: "Account", summary: "Matches of the current user's player", access: AccessAccount,
+		response: []models.Match{}},
+
+	// Organizations
+	{method: "GET", path: "/organizations", id: "getMyOrganizations", tag: "Organizations", summary: "Organizations the current user is a member of", access: AccessAccount,
+		response: []models.OrganizationMember{}},
+	{method: "POST", path: "/organizations", id: "createOrganization", tag: "Organizations", summary: "Create an organization owned by the current user", access: AccessAccount,
+		request: requests.CreateOrganization{}, status: 201, response: models.Organization{}},
+	{method: "GET", path: "/organizations/:id/members", id: "getOrganizationMembers", tag: "Organizations", summary: "Members of an organization", access: AccessAccount,
+		response: []models.OrganizationMember{}},
+	{method: "PUT", path: "/organizations/:id/members", id: "setOrganizationMember", tag: "Organizations", summary: "Add a user by email or change their role", access: AccessAccount,
+		request: requests.SetOrganizationMember{}, response: models.OrganizationMember{}},
+	{method: "DELETE", path: "/organizations/:id/members/:userId", id: "removeOrganizationMember", tag: "Organizations", summary: "Remove a member", access: AccessAccount,
+		response: message{}},
+
+	// Championships
+	{method: "GET", path: "/championships", id: "getChampionships", tag: "Championships", summary: "List championships", access: AccessUsers,
+		query: []Parameter{
+			queryParam("status", "Only championships in this status", &Schema{Ref: "#/components/schemas/ChampionshipStatus"}),
+			queryParam("season_id", "Only championships of this season", integer()),
+			queryParam("from", "Created at or after, YYYY-MM-DD or RFC 3339", stringSchema()),
+			queryParam("to", "Created at or before, YYYY-MM-DD (inclusive) or RFC 3339", stringSchema()),
+		}, sorting: &repository.ChampionshipSorting, response: []models.Championship{}},
+	{method: "GET", path: "/championships/:id", id: "getChampionship", tag: "Championships", summary: "Get a championship", access: AccessTenant,
+		etag: true, response: models.Championship{}},
+	{method: "POST", path: "/championships", id: "createChampionship", tag: "Championships", summary: "Create a championship owned by the current user", access: AccessUsers,
+		request: requests.CreateChampionship{}, status: 201, etag: true, response: models.Championship{}},
+	{method: "PUT", path: "/championships/:id", id: "updateChampionship", tag: "Championships", summary: "Update a championship, missing fields keep their values", access: AccessUsers,
+		request: requests.UpdateChampionship{}, ifMatch: true, etag: true, response: models.Championship{}},
+	{method: "DELETE", path: "/championships/:id", id: "deleteChampionship", tag: "Championships", summary: "Delete a championship", access: AccessUsers,
+		response: message{}},
+	{method: "POST", path: "/championships/:id/finalize", id: "finalizeChampionship", tag: "Championships", summary: "Finalize a championship", access: AccessUsers,
+		ifMatch: true, etag: true, response: models.Championship{}},
+	{method: "GET", path: "/championships/:id/standings", id: "getStandings", tag: "Championships", summary: "Standings", access: AccessTenant,
+		query: []Parameter{
+			queryParam("as_of", "Standings as they were at this time, YYYY-MM-DD (inclusive) or RFC 3339", stringSchema()),
+		}, response: []standings.Row{}},
+	{method: "GET", path: "/championships/:id/standings/history", id: "getStandingsHistory", tag: "Championships", summary: "Standings after every day or match", access: AccessTenant,
+		query: []Parameter{
+			queryParam("by", "date (default) or match", &Schema{Type: "string", Enum: []string{"date", "match"}}),
+		}, response: []service.StandingsSnapshot{}},
+	{method: "GET", path: "/championships/:id/projections", id: "getProjections", tag: "Championships", summary: "Simulated final positions", access: AccessTenant,
+		query: []Parameter{
+			queryParam("simulations", fmt.Sprintf("Number of simulated championships, defaults to %d. Only the default is cached.", projections.DefaultSimulations), integer()),
+		}, response: projections.Result{}},
+	{method: "GET", path: "/championships/:id/draw", id: "getDraw", tag: "Draws", summary: "Draw the matches were generated from", access: AccessTenant,
+		response: struct {
+			Method string                    `json:"method"`
+			Seed   *int64                    `json:"seed"`
+			Seeds  []models.ChampionshipSeed `json:"seeds"`
+		}{}},
+	{method: "POST", path: "/championships/:id/draw/preview", id: "previewDraw", tag: "Draws", summary: "Preview the draw generate-matches would make", access: AccessUsers,
+		request: service.DrawRequest{}, optionalBody: true, response: service.Draw{}},
+	{method: "POST", path: "/championships/:id/generate-matches", id: "generateMatches", tag: "Draws", summary: "Draw the players and create a round robin", access: AccessUsers,
+		request: service.DrawRequest{}, optionalBody: true, ifMatch: true, status: 201, response: generatedMatches{}},
+	{method: "POST", path: "/championships/:id/schedule", id: "scheduleMatches", tag: "Matches", summary: "Assign the pending matches to time slots", access: AccessUsers,
+		request: requests.Schedule{}, response: scheduledMatches{}, failures: map[int]interface{}{422: scheduleConflicts{}}},
+	{method: "GET", path: "/championships/:id/calendar.ics", id: "getChampionshipCalendar", tag: "Calendars", summary: "Scheduled matches of a championship", access: AccessFeed,
+		contentType: "text/calendar"},
+	{method: "GET", path: "/championships/:id/disputes", id: "getDisputes", tag: "Matches", summary: "Disputed results of a championship", access: AccessUsers,
+		response: []models.Match{}},
+	{method: "GET", path: "/championships/:id/members", id: "getChampionshipMembers", tag: "Championship members", summary: "Members of a championship with their email addresses, for organizers", access: AccessUsers,
+		response: []models.ChampionshipMember{}},
+	{method: "PUT", path: "/championships/:id/members", id: "setChampionshipMember", tag: "Championship members", summary: "Add a member or change their role", access: AccessUsers,
+		request: requests.SetChampionshipMember{}, response: models.ChampionshipMember{}},
+	{method: "DELETE", path: "/championships/:id/members/:userId", id: "removeChampionshipMember", tag: "Championship members", summary: "Remove a member", access: AccessUsers,
+		response: message{}},
+	{method: "GET", path: "/championships/:id/api-keys", id: "getAPIKeys", tag: "API keys", summary: "API keys of a championship", access: AccessUsers,
+		response: []models.APIKey{}},
+	{method: "POST", path: "/championships/:id/api-keys", id: "createAPIKey", tag: "API keys", summary: "Create an API key, the key is only returned once", access: AccessUsers,
+		request: requests.CreateAPIKey{}, status: 201, response: struct {
+			Key    string        `json:"key"`
+			APIKey models.APIKey `json:"api_key"`
+		}{}},
+	{method: "DELETE", path: "/championships/:id/api-keys/:keyId", id: "revokeAPIKey", tag: "API keys", summary: "Revoke an API key", access: AccessUsers,
+		response: models.APIKey{}},
+
+	// Players
+	{method: "GET", path: "/players", id: "getPlayers", tag: "Players", summary: "List players", access: AccessTenant,
+		query: []Parameter{
+			queryParam("championship_id", "Only players of this championship", integer()),
+			queryParam("name", "Only players whose name contains this text, ignoring case", stringSchema()),
+		}, sorting: &repository.PlayerSorting, response: []models.Player{}},
+	{method: "GET", path: "/players/:id", id: "getPlayer", tag: "Players", summary: "Get a player", access: AccessUsers,
+		etag: true, response: models.Player{}},
+	{method: "POST", path: "/players", id: "createPlayer", tag: "Players", summary: "Create a player", access: AccessUsers,
+		request: requests.CreatePlayer{}, status: 201, etag: true, response: models.Player{}},
+	{method: "PUT", path: "/players/:id", id: "updatePlayer", tag: "Players", summary: "Rename a player or replace their championships", access: AccessUsers,
+		request: requests.UpdatePlayer{}, ifMatch: true, etag: true, response: models.Player{}},
+	{method: "DELETE", path: "/players/:id", id: "deletePlayer", tag: "Players", summary: "Delete a player", access: AccessUsers,
+		response: message{}},
+	{method: "PUT", path: "/players/:id/user", id: "linkUser", tag: "Players", summary: "Link a member to the player they play as", access: AccessUsers,
+		request: requests.LinkUser{}, response: models.User{}},
+	{method: "DELETE", path: "/players/:id/user", id: "unlinkUser", tag: "Players", summary: "Unlink the user playing as a player", access: AccessUsers,
+		response: message{}},
+	{method: "GET", path: "/players/:id/stats", id: "getPlayerStats", tag: "Statistics", summary: "Statistics of a player", access: AccessUsers,
+		query: finishedMatchFilters(), response: struct {
+			Player models.Player     `json:"player"`
+			Stats  stats.PlayerStats `json:"stats"`
+		}{}},
+	{method: "GET", path: "/players/:id/vs/:otherId", id: "getHeadToHead", tag: "Statistics", summary: "Head to head record of two players", access: AccessUsers,
+		query: finishedMatchFilters(), response: struct {
+			Player   models.Player    `json:"player"`
+			Opponent models.Player    `json:"opponent"`
+			Record   stats.HeadToHead `json:"record"`
+			Matches  []models.Match   `json:"matches"`
+		}{}},
+	{method: "GET", path: "/players/:id/achievements", id: "getAchievements", tag: "Achievements", summary: "Badges a player earned", access: AccessUsers,
+		response: []achievements.Earned{}},
+	{method: "GET", path: "/badges", id: "getBadges", tag: "Achievements", summary: "All badges", access: AccessUsers,
+		response: []struct {
+			Badge       models.Badge `json:"badge"`
+			Description string       `json:"description"`
+		}{}},
+	{method: "GET", path: "/players/:id/calendar.ics", id: "getPlayerCalendar", tag: "Calendars", summary: "Scheduled matches of a player", access: AccessFeed,
+		contentType: "text/calendar"},
+	{method: "GET", path: "/players/:id/unavailability", id: "getUnavailability", tag: "Players", summary: "Times a player cannot play", access: AccessUsers,
+		response: []models.PlayerUnavailability{}},
+	{method: "POST", path: "/players/:id/unavailability", id: "createUnavailability", tag: "Players", summary: "Add a weekday or date range a player cannot play", access: AccessUsers,
+		request: requests.CreateUnavailability{}, status: 201, response: models.PlayerUnavailability{}},
+	{method: "DELETE", path: "/players/:id/unavailability/:unavailabilityId", id: "deleteUnavailability", tag: "Players", summary: "Remove an unavailability", access: AccessUsers,
+		response: message{}},
+
+	// Matches
+	{method: "GET", path: "/matches", id: "getMatches", tag: "Matches", summary: "List matches", access: AccessTenant,
+		query: []Parameter{
+			queryParam("championship_id", "Only matches of this championship", integer()),
+			queryParam("status", "Only matches in this status", &Schema{Ref: "#/components/schemas/MatchStatus"}),
+			queryParam("player", "Only matches of the player with this name", stringSchema()),
+			queryParam("player_id", "Only matches of this player", integer()),
+			queryParam("game", "Only matches of this game", stringSchema()),
+			queryParam("from", "Finished, else scheduled, else created at or after, YYYY-MM-DD or RFC 3339", stringSchema()),
+			queryParam("to", "Finished, else scheduled, else created at or before, YYYY-MM-DD (inclusive) or RFC 3339", stringSchema()),
+		}, sorting: &repository.MatchSorting, response: []models.Match{}},
+	{method: "GET", path: "/matches/:id", id: "getMatch", tag: "Matches", summary: "Get a match", access: AccessTenant,
+		etag: true, response: models.Match{}},
+	{method: "POST", path: "/matches", id: "createMatch", tag: "Matches", summary: "Create a match", access: AccessUsers,
+		request: requests.CreateMatch{}, v2Request: requests.CreateMatchV2{}, status: 201, etag: true, response: models.Match{}},
+	{method: "POST", path: "/matches/:id/start", id: "startMatch", tag: "Matches", summary: "Start a match", access: AccessTenant,
+		ifMatch: true, etag: true, response: models.Match{}},
+	{method: "PUT", path: "/matches/:id/score", id: "updateMatchScore", tag: "Matches", summary: "Update the score of a started match", access: AccessTenant,
+		request: requests.Score{}, ifMatch: true, etag: true, response: models.Match{}},
+	{method: "POST", path: "/matches/:id/finish", id: "finishMatch", tag: "Matches", summary: "Finish a match, or report its result for confirmation", access: AccessTenant,
+		ifMatch: true, etag: true, response: models.Match{}},
+	{method: "POST", path: "/matches/:id/confirm", id: "confirmResult", tag: "Matches", summary: "Confirm the result the opponent reported", access: AccessUsers,
+		ifMatch: true, etag: true, response: models.Match{}},
+	{method: "POST", path: "/matches/:id/dispute", id: "disputeResult", tag: "Matches", summary: "Dispute the result the opponent reported", access: AccessUsers,
+		request: requests.Dispute{}, ifMatch: true, etag: true, response: models.Match{}},
+	{method: "POST", path: "/matches/:id/resolve", id: "resolveDispute", tag: "Matches", summary: "Set the official result of a disputed match", access: AccessUsers,
+		request: requests.Score{}, ifMatch: true, etag: true, response: models.Match{}},
+	{method: "GET", path: "/records", id: "getRecords", tag: "Statistics", summary: "All-time records of the organization", access: AccessUsers,
+		response: []models.Record{}},
+
+	// Seasons
+	{method: "GET", path: "/seasons", id: "getSeasons", tag: "Seasons", summary: "List seasons", access: AccessUsers,
+		response: []models.Season{}},
+	{method: "GET", path: "/seasons/:id", id: "getSeason", tag: "Seasons", summary: "Get a season with its championships", access: AccessUsers,
+		etag: true, response: models.Season{}},
+	{method: "GET", path: "/seasons/:id/standings", id: "getSeasonStandings", tag: "Seasons", summary: "Weighted standings over the season's championships", access: AccessUsers,
+		response: []standings.SeasonStanding{}},
+	{method: "POST", path: "/seasons", id: "createSeason", tag: "Seasons", summary: "Create a season", access: AccessUsers,
+		request: requests.SaveSeason{}, status: 201, etag: true, response: models.Season{}},
+	{method: "PUT", path: "/seasons/:id", id: "updateSeason", tag: "Seasons", summary: "Update a season", access: AccessUsers,
+		request: requests.SaveSeason{}, ifMatch: true, etag: true, response: models.Season{}},
+	{method: "DELETE", path: "/seasons/:id", id: "deleteSeason", tag: "Seasons", summary: "Delete a season, its championships are kept", access: AccessUsers,
+		response: message{}},
+	{method: "PUT", path: "/seasons/:id/championships/:championshipId", id: "addSeasonChampionship", tag: "Seasons", summary: "Add a championship to the season or change its weight", access: AccessUsers,
+		request: requests.SeasonChampionship{}, optionalBody: true, ifMatch: true, etag: true, response: models.Championship{}},
+	{method: "DELETE", path: "/seasons/:id/championships/:championshipId", id: "removeSeasonChampionship", tag: "Seasons", summary: "Remove a championship from the season", access: AccessUsers,
+		ifMatch: true, etag: true, response: message{}},
+}
+
+// enums lists the values of the string types used by the models
+func enums(s *schemas) {
+	enum(s, models.ChampionshipStatusDraft, models.ChampionshipStatusFinalized)
+	enum(s, models.MatchStatusPending, models.MatchStatusStarted, models.MatchStatusFinished,
+		models.MatchStatusPendingConfirmation, models.MatchStatusDisputed)
+	enum(s, models.OrganizationRoleOwner, models.OrganizationRoleAdmin, models.OrganizationRoleMember)
+	enum(s, models.ChampionshipRoleOwner, models.ChampionshipRoleOrganizer, models.ChampionshipRoleReferee,
+		models.ChampionshipRolePlayer, models.ChampionshipRoleViewer)
+	enum(s, models.APIKeyScopeRead, models.APIKeyScopeScoreEntry)
+	enum(s, models.UnavailabilityKindWeekday, models.UnavailabilityKindDateRange)
+	enum(s, models.BadgeFirstWin, models.BadgeWinStreak10, models.BadgePerfectRoundRobin, models.BadgeGiantKiller)
+	enum(s, models.RecordKinds...)
+	enum(s, seeding.MethodManual, seeding.MethodRating, seeding.MethodPreviousResult, seeding.MethodRandom)
+	enum(s, stats.OutcomeWin, stats.OutcomeDraw, stats.OutcomeLoss)
+}
+
+// finishedMatchFilters are the filters of the statistics endpoints
+func finishedMatchFilters() []Parameter {
+	return []Parameter{
+		queryParam("championship_id", "Only matches of this championship", integer()),
+		queryParam("from", "Finished at or after, YYYY-MM-DD or RFC 3339", stringSchema()),
+		queryParam("to", "Finished at or before, YYYY-MM-DD (inclusive) or RFC 3339", stringSchema()),
+	}
+}
@@ -0,0 +1,134 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report validation errors by JSON name instead of Go field name
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// Binding responds to an error of binding the request body, describing the
+// offending fields without exposing Go types
+func Binding(c *gin.Context, err error) {
+	var fieldErrs FieldErrors
+	if errors.As(err, &fieldErrs) {
+		Invalid(c, fieldErrs...)
+		return
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			fields = append(fields, validationError(fieldErr))
+		}
+		Invalid(c, fields...)
+		return
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		Invalid(c, FieldError{
+			Field:   typeErr.Field,
+			Code:    FieldInvalidType,
+			Message: fmt.Sprintf("%s must be %s", typeErr.Field, jsonType(typeErr.Type)),
+		})
+		return
+	}
+
+	var timeErr *time.ParseError
+	if errors.As(err, &timeErr) {
+		Abort(c, http.StatusBadRequest, CodeMalformedBody, "Times must be RFC 3339 timestamps")
+		return
+	}
+
+	if errors.Is(err, io.EOF) {
+		Abort(c, http.StatusBadRequest, CodeMalformedBody, "Request body is required")
+		return
+	}
+
+	Abort(c, http.StatusBadRequest, CodeMalformedBody, "Request body must be a valid JSON object")
+}
+
+// field returns the JSON path of a validated field, without the root struct
+func field(fieldErr validator.FieldError) string {
+	_, path, found := strings.Cut(fieldErr.Namespace(), ".")
+	if !found {
+		return fieldErr.Field()
+	}
+	return path
+}
+
+func validationError(fieldErr validator.FieldError) FieldError {
+	name := field(fieldErr)
+	text := fieldErr.Kind() == reflect.String
+
+	switch fieldErr.Tag() {
+	case "required":
+		return FieldError{Field: name, Code: FieldRequired, Message: name + " is required"}
+	case "min", "gte":
+		if text && fieldErr.Param() == "1" {
+			return FieldError{Field: name, Code: FieldTooShort, Message: name + " must not be empty"}
+		}
+		if text {
+			return FieldError{Field: name, Code: FieldTooShort, Message: fmt.Sprintf("%s must be at least %s characters long", name, fieldErr.Param())}
+		}
+		return FieldError{Field: name, Code: FieldOutOfRange, Message: fmt.Sprintf("%s must be at least %s", name, fieldErr.Param())}
+	case "max", "lte":
+		if text {
+			return FieldError{Field: name, Code: FieldTooLong, Message: fmt.Sprintf("%s must be at most %s characters long", name, fieldErr.Param())}
+		}
+		return FieldError{Field: name, Code: FieldOutOfRange, Message: fmt.Sprintf("%s must be at most %s", name, fieldErr.Param())}
+	case "gt":
+		return FieldError{Field: name, Code: FieldOutOfRange, Message: fmt.Sprintf("%s must be greater than %s", name, fieldErr.Param())}
+	case "eq":
+		return FieldError{Field: name, Code: FieldInvalidValue, Message: fmt.Sprintf("%s must be %s", name, fieldErr.Param())}
+	case "oneof":
+		return FieldError{Field: name, Code: FieldInvalidValue, Message: fmt.Sprintf("%s must be one of %s", name, strings.ReplaceAll(fieldErr.Param(), " ", ", "))}
+	case "email":
+		return FieldError{Field: name, Code: FieldInvalidFormat, Message: name + " must be an email address"}
+	}
+	return FieldError{Field: name, Code: FieldInvalidValue, Message: name + " is invalid"}
+}
+
+// jsonType names the JSON type of a Go type, with an article
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a different type"
+}
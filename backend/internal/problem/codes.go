@@ -0,0 +1,80 @@
+package problem
+
+// Codes of problems. They are part of the API: clients branch on them, so
+// existing codes must never change meaning.
+const (
+	// Requests
+	CodeValidationFailed = "validation_failed"
+	CodeMalformedBody    = "malformed_body"
+	CodeInvalidID        = "invalid_id"
+	CodeRouteNotFound    = "route_not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeInternal         = "internal_error"
+
+	// Authentication
+	CodeAuthenticationRequired = "authentication_required"
+	CodeInvalidCredentials     = "invalid_credentials"
+	CodeInvalidToken           = "invalid_token"
+	CodeInvalidRefreshToken    = "invalid_refresh_token"
+	CodeInvalidAPIKey          = "invalid_api_key"
+	CodeAPIKeyExpired          = "api_key_expired"
+
+	// Permissions
+	CodeForbidden             = "insufficient_permissions"
+	CodeUserRequired          = "user_account_required"
+	CodeOwnerRequired         = "owner_required"
+	CodeNotOrganizationMember = "not_organization_member"
+	CodeOrganizationRequired  = "organization_required"
+	CodeNotOpponent           = "not_opponent"
+	CodeNotOwnMatch           = "not_own_match"
+
+	// Records that do not exist
+	CodeAPIKeyNotFound         = "api_key_not_found"
+	CodeChampionshipNotFound   = "championship_not_found"
+	CodeDrawNotFound           = "draw_not_found"
+	CodeMatchNotFound          = "match_not_found"
+	CodeMemberNotFound         = "member_not_found"
+	CodeOrganizationNotFound   = "organization_not_found"
+	CodePlayerNotFound         = "player_not_found"
+	CodeSeasonNotFound         = "season_not_found"
+	CodeUnavailabilityNotFound = "unavailability_not_found"
+	CodeUserNotFound           = "user_not_found"
+
+	// Business rules and conflicting state
+	CodeAPIKeyRevoked             = "api_key_revoked"
+	CodeChampionshipFinalized     = "championship_finalized"
+	CodeChampionshipNotFinalized  = "championship_not_finalized"
+	CodeChampionshipInOtherSeason = "championship_in_other_season"
+	CodeChampionshipNotInSeason   = "championship_not_in_season"
+	CodeConcurrentUpdate          = "concurrent_update"
+	CodeEmailTaken                = "email_taken"
+	CodeLastOwner                 = "last_owner"
+	CodeMatchNotDisputed          = "match_not_disputed"
+	CodeMatchNotPending           = "match_not_pending"
+	CodeMatchNotStarted           = "match_not_started"
+	CodeMatchesExist              = "matches_exist"
+	CodeNoPendingMatches          = "no_pending_matches"
+	CodeNoReportedResult          = "no_reported_result"
+	CodeNotEnoughPlayers          = "not_enough_players"
+	CodePlayerAlreadyLinked       = "player_already_linked"
+	CodePlayerNotInChampionship   = "player_not_in_championship"
+	CodePlayerNotLinked           = "player_not_linked"
+	CodeSamePlayers               = "same_players"
+	CodeScheduleConflicts         = "schedule_conflicts"
+	CodeUserNotInOrganization     = "user_not_in_organization"
+	CodeVersionMismatch           = "version_mismatch"
+)
+
+// Codes of field errors
+const (
+	FieldRequired      = "required"
+	FieldInvalidType   = "invalid_type"
+	FieldInvalidValue  = "invalid_value"
+	FieldInvalidFormat = "invalid_format"
+	FieldOutOfRange    = "out_of_range"
+	FieldTooShort      = "too_short"
+	FieldTooLong       = "too_long"
+	FieldNotFound      = "not_found"
+	FieldUnknown       = "unknown_field"
+	FieldReadOnly      = "read_only"
+)
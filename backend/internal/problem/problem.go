@@ -0,0 +1,132 @@
+// Package problem writes error responses as RFC 7807 problem details.
+// Every problem has a stable code clients can branch on; the detail is a
+// message for humans and may change.
+package problem
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	// URI of the problem type, derived from the code
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	// Same as Detail, for clients written against the former {"error": "..."} responses
+	Error string `json:"error"`
+	// Additional members specific to the problem type
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// MarshalJSON adds the extension members next to the standard ones
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type standard Problem
+	data, err := json.Marshal(standard(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	members := make(map[string]interface{})
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range p.Extensions {
+		if _, taken := members[name]; !taken {
+			members[name] = value
+		}
+	}
+	return json.Marshal(members)
+}
+
+// FieldError describes an invalid field of the request
+type FieldError struct {
+	// JSON name or query parameter of the field
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// FieldErrors is an error made of invalid fields, which Binding responds with
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// New builds a problem
+func New(status int, code string, detail string, errors ...FieldError) Problem {
+	return Problem{
+		Type:   TypeURI(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Errors: errors,
+		Error:  detail,
+	}
+}
+
+// TypeURI returns the problem type of a code
+func TypeURI(code string) string {
+	return "urn:scoretracker:problem:" + code
+}
+
+// Write sends a problem and aborts the remaining handlers
+func Write(c *gin.Context, p Problem) {
+	p.Instance = c.Request.URL.Path
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// Abort responds with a problem without field errors
+func Abort(c *gin.Context, status int, code string, detail string) {
+	Write(c, New(status, code, detail))
+}
+
+// Invalid responds that fields of the request are invalid
+func Invalid(c *gin.Context, errors ...FieldError) {
+	detail := "The request is invalid"
+	if len(errors) == 1 {
+		detail = errors[0].Message
+	}
+	Write(c, New(http.StatusBadRequest, CodeValidationFailed, detail, errors...))
+}
+
+// Internal logs err and responds with a generic problem. The detail is sent
+// to the client and must not contain err.
+func Internal(c *gin.Context, detail string, err error) {
+	log.Printf("%s %s: %s: %v", c.Request.Method, c.Request.URL.Path, detail, err)
+	Abort(c, http.StatusInternalServerError, CodeInternal, detail)
+}
+
+// NoRoute responds to requests for unknown paths
+func NoRoute(c *gin.Context) {
+	Abort(c, http.StatusNotFound, CodeRouteNotFound, "No endpoint matches "+c.Request.URL.Path)
+}
+
+// NoMethod responds to requests with a method the path does not support
+func NoMethod(c *gin.Context) {
+	Abort(c, http.StatusMethodNotAllowed, CodeMethodNotAllowed, c.Request.Method+" is not supported by "+c.Request.URL.Path)
+}
+
+// Recover responds to panics of handlers, which gin.CustomRecovery has logged
+func Recover(c *gin.Context, recovered interface{}) {
+	Abort(c, http.StatusInternalServerError, CodeInternal, "Internal server error")
+}
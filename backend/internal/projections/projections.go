@@ -0,0 +1,267 @@
+package projections
+
+import (
+	"container/list"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/standings"
+	"scoretracker/backend/internal/stats"
+)
+
+// DefaultSimulations is the number of simulated seasons if none is requested
+const DefaultSimulations = 10000
+
+// Strength estimates how a player performs, based on all historic results
+type Strength struct {
+	// Smoothed share of points won, where a draw counts half
+	WinRate float64
+	// Smoothed share of matches drawn
+	DrawRate float64
+}
+
+// Strengths derives the strength of every player from finished matches.
+// Results are smoothed towards an average player so that newcomers with few
+// matches do not get extreme probabilities.
+func Strengths(history []models.Match) map[string]Strength {
+	records := make(map[string]*stats.Record)
+	for _, match := range history {
+		for _, player := range []string{match.Player1, match.Player2} {
+			if records[player] == nil {
+				records[player] = &stats.Record{}
+			}
+			record := records[player]
+			record.Played++
+			switch stats.OutcomeFor(match, player) {
+			case stats.OutcomeWin:
+				record.Wins++
+			case stats.OutcomeDraw:
+				record.Draws++
+			}
+		}
+	}
+
+	strengths := make(map[string]Strength, len(records))
+	for player, record := range records {
+		strengths[player] = Rate(*record)
+	}
+
+	return strengths
+}
+
+// Rate derives the strength of a player from its record of finished matches
+func Rate(record stats.Record) Strength {
+	played := float64(record.Played)
+	return Strength{
+		WinRate:  (float64(record.Wins) + 0.5*float64(record.Draws) + 1) / (played + 2),
+		DrawRate: (float64(record.Draws) + 0.2) / (played + 1),
+	}
+}
+
+// outcomeProbabilities returns the chance that player1 wins and that the match
+// ends in a draw, using a Bradley-Terry model on the players' win rates
+func outcomeProbabilities(player1 Strength, player2 Strength) (win float64, draw float64) {
+	draw = (player1.DrawRate + player2.DrawRate) / 2
+	return (1 - draw) * player1.WinRate / (player1.WinRate + player2.WinRate), draw
+}
+
+// Projection holds the simulated outcome for one player. Positions[0] is the
+// probability of finishing first.
+type Projection struct {
+	PlayerName     string    `json:"player_name"`
+	Points         int       `json:"points"`
+	ExpectedPoints float64   `json:"expected_points"`
+	Positions      []float64 `json:"positions"`
+}
+
+type Result struct {
+	Simulations      int          `json:"simulations"`
+	RemainingMatches int          `json:"remaining_matches"`
+	GeneratedAt      time.Time    `json:"generated_at"`
+	Projections      []Projection `json:"projections"`
+}
+
+// Simulate plays the remaining matches of a championship the given number of
+// times, spread over all CPUs, and counts how often each player finishes in
+// each position. Positions are decided like the real standings.
+func Simulate(players []string, finished []models.Match, remaining []models.Match, strengths map[string]Strength, simulations int) Result {
+	average := Strength{WinRate: 0.5, DrawRate: 0.2}
+	type matchup struct {
+		win, draw float64
+	}
+	matchups := make([]matchup, len(remaining))
+	for i, match := range remaining {
+		player1, ok := strengths[match.Player1]
+		if !ok {
+			player1 = average
+		}
+		player2, ok := strengths[match.Player2]
+		if !ok {
+			player2 = average
+		}
+		matchups[i].win, matchups[i].draw = outcomeProbabilities(player1, player2)
+	}
+
+	index := make(map[string]int, len(players))
+	for i, player := range players {
+		index[player] = i
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > simulations {
+		workers = simulations
+	}
+
+	positionCounts := make([][]int, len(players))
+	for i := range positionCounts {
+		positionCounts[i] = make([]int, len(players))
+	}
+	totalPoints := make([]int, len(players))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	seed := time.Now().UnixNano()
+
+	for worker := 0; worker < workers; worker++ {
+		runs := simulations / workers
+		if worker < simulations%workers {
+			runs++
+		}
+
+		wg.Add(1)
+		go func(runs int, rng *rand.Rand) {
+			defer wg.Done()
+
+			counts := make([][]int, len(players))
+			for i := range counts {
+				counts[i] = make([]int, len(players))
+			}
+			sums := make([]int, len(players))
+
+			matches := make([]models.Match, len(finished), len(finished)+len(remaining))
+			copy(matches, finished)
+
+			for run := 0; run < runs; run++ {
+				matches = matches[:len(finished)]
+				for i, match := range remaining {
+					roll := rng.Float64()
+					switch {
+					case roll < matchups[i].win:
+						match.Winner = &remaining[i].Player1
+					case roll < matchups[i].win+matchups[i].draw:
+						match.Winner = nil
+					default:
+						match.Winner = &remaining[i].Player2
+					}
+					matches = append(matches, match)
+				}
+
+				points := standings.Points(matches)
+				for position, player := range standings.Order(players, points, matches) {
+					counts[index[player]][position]++
+					sums[index[player]] += points[player]
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for i := range counts {
+				for position, count := range counts[i] {
+					positionCounts[i][position] += count
+				}
+				totalPoints[i] += sums[i]
+			}
+		}(runs, rand.New(rand.NewSource(seed+int64(worker))))
+	}
+	wg.Wait()
+
+	current := standings.Points(finished)
+	result := Result{
+		Simulations:      simulations,
+		RemainingMatches: len(remaining),
+		GeneratedAt:      time.Now(),
+		Projections:      make([]Projection, 0, len(players)),
+	}
+	for i, player := range players {
+		projection := Projection{
+			PlayerName:     player,
+			Points:         current[player],
+			ExpectedPoints: float64(totalPoints[i]) / float64(simulations),
+			Positions:      make([]float64, len(players)),
+		}
+		for position, count := range positionCounts[i] {
+			projection.Positions[position] = float64(count) / float64(simulations)
+		}
+		result.Projections = append(result.Projections, projection)
+	}
+
+	sort.SliceStable(result.Projections, func(i, j int) bool {
+		return result.Projections[i].ExpectedPoints > result.Projections[j].ExpectedPoints
+	})
+
+	return result
+}
+
+// CacheSize is the number of championships whose projection is cached. The
+// least recently used projection is evicted first.
+const CacheSize = 1000
+
+// Cache keeps the latest projection with the default number of simulations
+// per championship until its version changes. Other simulation counts are
+// not cached, so callers cannot fill the cache with variants.
+type Cache struct {
+	mu      sync.Mutex
+	entries map[uint]*list.Element
+	// Least recently used at the back
+	usage *list.List
+}
+
+type cacheEntry struct {
+	championshipID uint
+	version        string
+	result         Result
+}
+
+func NewCache() *Cache {
+	return &Cache{entries: make(map[uint]*list.Element), usage: list.New()}
+}
+
+// Get returns the cached projection if it was computed for the same version
+// of the championship's results and the results the strengths are based on
+func (c *Cache) Get(championshipID uint, version string) (Result, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[championshipID]
+	if !ok {
+		return Result{}, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if entry.version != version {
+		return Result{}, false
+	}
+	c.usage.MoveToFront(element)
+	return entry.result, true
+}
+
+func (c *Cache) Put(championshipID uint, version string, result Result) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[championshipID]; ok {
+		element.Value = &cacheEntry{championshipID: championshipID, version: version, result: result}
+		c.usage.MoveToFront(element)
+		return
+	}
+
+	c.entries[championshipID] = c.usage.PushFront(&cacheEntry{championshipID: championshipID, version: version, result: result})
+	if c.usage.Len() > CacheSize {
+		oldest := c.usage.Back()
+		c.usage.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).championshipID)
+	}
+}
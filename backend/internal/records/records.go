@@ -0,0 +1,238 @@
+package records
+
+import (
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/standings"
+	"scoretracker/backend/internal/stats"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Recompute rebuilds all records of an organization from its match data, e.g.
+// to backfill them. Finished matches update the records with Update instead.
+func Recompute(db *gorm.DB, organizationID uint) error {
+	var championships []models.Championship
+	if err := db.Preload("Players").Where("organization_id = ?", organizationID).Find(&championships).Error; err != nil {
+		return err
+	}
+
+	// Matches of deleted championships do not count
+	var championshipIDs []uint
+	for _, championship := range championships {
+		championshipIDs = append(championshipIDs, championship.ID)
+	}
+
+	var matches []models.Match
+	if len(championshipIDs) > 0 {
+		if err := db.Where("organization_id = ? AND championship_id IN ?", organizationID, championshipIDs).
+			Order("finished_at ASC, id ASC").Find(&matches).Error; err != nil {
+			return err
+		}
+	}
+
+	records := Compute(championships, matches)
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_id = ?", organizationID).Delete(&models.Record{}).Error; err != nil {
+			return err
+		}
+		for i := range records {
+			records[i].OrganizationID = organizationID
+		}
+		if len(records) == 0 {
+			return nil
+		}
+		return tx.Create(&records).Error
+	})
+}
+
+// Compute derives the records from championships and all of their matches.
+// Matches must be ordered by finished_at; records go to whoever set them first.
+func Compute(championships []models.Championship, matches []models.Match) []models.Record {
+	best := make(map[models.RecordKind]*models.Record)
+	improve := func(kind models.RecordKind, player string, value int, match models.Match) {
+		if current := best[kind]; current != nil && value <= current.Value {
+			return
+		}
+		best[kind] = &models.Record{
+			Kind:       kind,
+			PlayerName: player,
+			Value:      value,
+			MatchID:    match.ID,
+			SetAt:      match.FinishedAt,
+		}
+	}
+
+	played := make(map[string]int)
+	unbeaten := make(map[string]int)
+
+	for _, match := range matches {
+		if match.Status != models.MatchStatusFinished {
+			continue
+		}
+
+		for _, player := range []string{match.Player1, match.Player2} {
+			scoreFor, scoreAgainst := stats.Scores(match, player)
+			improve(models.RecordHighestScore, player, scoreFor, match)
+
+			played[player]++
+			improve(models.RecordMostMatchesPlayed, player, played[player], match)
+
+			switch stats.OutcomeFor(match, player) {
+			case stats.OutcomeWin:
+				improve(models.RecordBiggestMargin, player, scoreFor-scoreAgainst, match)
+				unbeaten[player]++
+			case stats.OutcomeDraw:
+				unbeaten[player]++
+			default:
+				unbeaten[player] = 0
+			}
+			if unbeaten[player] > 0 {
+				improve(models.RecordLongestUnbeatenRun, player, unbeaten[player], match)
+			}
+		}
+	}
+
+	// A championship is won once it is finalized and all of its matches are finished
+	wins := make(map[string]int)
+	for _, completion := range standings.Completed(championships, matches) {
+		winner := completion.Ranking[0]
+		wins[winner]++
+		improve(models.RecordMostChampionshipsWon, winner, wins[winner], completion.LastMatch)
+	}
+
+	records := make([]models.Record, 0, len(best))
+	for _, kind := range models.RecordKinds {
+		if record := best[kind]; record != nil {
+			records = append(records, *record)
+		}
+	}
+
+	return records
+}
+
+// Update takes the records the players of a newly finished match set with it.
+// It only reads the history of the match's players and championship, and can
+// be repeated: values equal to a record leave it to whoever set it first.
+func Update(db *gorm.DB, match models.Match) error {
+	if match.Status != models.MatchStatusFinished {
+		return nil
+	}
+
+	var championship models.Championship
+	if err := db.Preload("Players").Where("organization_id = ?", match.OrganizationID).
+		First(&championship, match.ChampionshipID).Error; err != nil {
+		// Matches of deleted championships do not count
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+
+	var candidates []models.Record
+	candidate := func(kind models.RecordKind, player string, value int) {
+		candidates = append(candidates, models.Record{
+			OrganizationID: match.OrganizationID,
+			Kind:           kind,
+			PlayerName:     player,
+			Value:          value,
+			MatchID:        match.ID,
+			SetAt:          match.FinishedAt,
+		})
+	}
+
+	for _, player := range []string{match.Player1, match.Player2} {
+		history, err := playerResults(db, match.OrganizationID, player)
+		if err != nil {
+			return err
+		}
+
+		scoreFor, scoreAgainst := stats.Scores(match, player)
+		candidate(models.RecordHighestScore, player, scoreFor)
+		candidate(models.RecordMostMatchesPlayed, player, len(history))
+		if stats.OutcomeFor(match, player) == stats.OutcomeWin {
+			candidate(models.RecordBiggestMargin, player, scoreFor-scoreAgainst)
+		}
+
+		// The run that ends with this match, newest first
+		unbeaten := 0
+		for i := len(history) - 1; i >= 0 && stats.OutcomeFor(history[i], player) != stats.OutcomeLoss; i-- {
+			unbeaten++
+		}
+		if unbeaten > 0 {
+			candidate(models.RecordLongestUnbeatenRun, player, unbeaten)
+		}
+	}
+
+	// The match may complete its championship
+	var matches []models.Match
+	if err := db.Where("championship_id = ?", championship.ID).Find(&matches).Error; err != nil {
+		return err
+	}
+	if completed := standings.Completed([]models.Championship{championship}, matches); len(completed) > 0 && completed[0].LastMatch.ID == match.ID {
+		winner := completed[0].Ranking[0]
+		wins, err := championshipsWon(db, match.OrganizationID, winner)
+		if err != nil {
+			return err
+		}
+		candidate(models.RecordMostChampionshipsWon, winner, wins)
+	}
+
+	// A record is only taken over by a strictly better value, which also
+	// holds for concurrent updates
+	for _, record := range candidates {
+		if err := db.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "organization_id"}, {Name: "kind"}},
+			DoUpdates: clause.AssignmentColumns([]string{"player_name", "value", "match_id", "set_at", "updated_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "records.value < excluded.value"}}},
+		}).Create(&record).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// playerResults returns the finished matches of a player in championships that
+// were not deleted, in the order they were finished
+func playerResults(db *gorm.DB, organizationID uint, player string) ([]models.Match, error) {
+	var matches []models.Match
+	err := db.Where("organization_id = ? AND status = ? AND (player1 = ? OR player2 = ?)",
+		organizationID, models.MatchStatusFinished, player, player).
+		Where("championship_id IN (?)", db.Model(&models.Championship{}).Select("id").Where("organization_id = ?", organizationID)).
+		Order("finished_at ASC, id ASC").Find(&matches).Error
+	return matches, err
+}
+
+// championshipsWon counts the completed championships the player won
+func championshipsWon(db *gorm.DB, organizationID uint, player string) (int, error) {
+	var championships []models.Championship
+	if err := db.Preload("Players").
+		Where("organization_id = ? AND status = ?", organizationID, models.ChampionshipStatusFinalized).
+		Where("id IN (?)", db.Table("player_championships").Select("player_championships.championship_id").
+			Joins("JOIN players ON players.id = player_championships.player_id").
+			Where("players.organization_id = ? AND players.name = ? AND players.deleted_at IS NULL", organizationID, player)).
+		Find(&championships).Error; err != nil {
+		return 0, err
+	}
+	if len(championships) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uint, 0, len(championships))
+	for _, championship := range championships {
+		ids = append(ids, championship.ID)
+	}
+	var matches []models.Match
+	if err := db.Where("championship_id IN ?", ids).Find(&matches).Error; err != nil {
+		return 0, err
+	}
+
+	wins := 0
+	for _, completion := range standings.Completed(championships, matches) {
+		if completion.Ranking[0] == player {
+			wins++
+		}
+	}
+	return wins, nil
+}
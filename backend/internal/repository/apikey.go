@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	// List returns the API keys of a championship, newest first
+	List(championshipID uint) ([]models.APIKey, error)
+	// Get loads an API key of the championship
+	Get(championshipID uint, id uint) (models.APIKey, error)
+	Create(key *models.APIKey) error
+	Revoke(key *models.APIKey, at time.Time) error
+}
+
+type gormAPIKeyRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormAPIKeyRepository) List(championshipID uint) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Where("championship_id = ?", championshipID).Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *gormAPIKeyRepository) Get(championshipID uint, id uint) (models.APIKey, error) {
+	var key models.APIKey
+	err := locked(r.db, r.lock).Where("championship_id = ?", championshipID).First(&key, id).Error
+	return key, translate(err)
+}
+
+func (r *gormAPIKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *gormAPIKeyRepository) Revoke(key *models.APIKey, at time.Time) error {
+	return r.db.Model(key).Update("revoked_at", at).Error
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ChampionshipRepository interface {
+	// List returns the championships of the organization that pass the
+	// filter, newest first unless sorted otherwise
+	List(organizationID uint, filter ChampionshipFilter, options ListOptions) ([]models.Championship, Page, error)
+	// Get loads a championship of the organization with the given associations
+	Get(organizationID uint, id uint, preloads ...string) (models.Championship, error)
+	// FindByIDs loads the championships of the organization among ids, ordered
+	// by ID so that transactions lock them in the same order
+	FindByIDs(organizationID uint, ids []uint) ([]models.Championship, error)
+	Create(championship *models.Championship) error
+	Save(championship *models.Championship) error
+	Delete(championship *models.Championship) error
+	UpdateStatus(id uint, status models.ChampionshipStatus) error
+	AddMember(member *models.ChampionshipMember) error
+	// InSeason returns the championships of a season with their players, in
+	// the order they were created
+	InSeason(seasonID uint) ([]models.Championship, error)
+	CountPlayers(id uint) (int64, error)
+	Players(id uint) ([]models.Player, error)
+	// SaveDraw records how the players of a championship were seeded
+	SaveDraw(id uint, method string, seed *int64, playerIDs []uint) error
+}
+
+// ChampionshipFilter selects championships, zero fields select all
+type ChampionshipFilter struct {
+	Status   models.ChampionshipStatus
+	SeasonID *uint
+	// Inclusive bounds of the creation time
+	From *time.Time
+	To   *time.Time
+}
+
+var championshipListing = listing[models.Championship]{
+	keys: map[string]sortKey[models.Championship]{
+		"id":         idKey("championships.id", func(c models.Championship) uint { return c.ID }),
+		"created_at": timeKey("championships.created_at", false, func(c models.Championship) *time.Time { return &c.CreatedAt }),
+		"name":       stringKey("championships.name", func(c models.Championship) string { return c.Name }),
+		"status":     stringKey("championships.status", func(c models.Championship) string { return string(c.Status) }),
+	},
+	defaultSort: "-created_at",
+	idColumn:    "championships.id",
+	id:          func(c models.Championship) uint { return c.ID },
+}
+
+type gormChampionshipRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormChampionshipRepository) List(organizationID uint, filter ChampionshipFilter, options ListOptions) ([]models.Championship, Page, error) {
+	query := r.db.Model(&models.Championship{}).Where("championships.organization_id = ?", organizationID)
+	if filter.Status != "" {
+		query = query.Where("championships.status = ?", filter.Status)
+	}
+	if filter.SeasonID != nil {
+		query = query.Where("championships.season_id = ?", *filter.SeasonID)
+	}
+	if filter.From != nil {
+		query = query.Where("championships.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("championships.created_at <= ?", *filter.To)
+	}
+
+	return championshipListing.list(query, options)
+}
+
+func (r *gormChampionshipRepository) Get(organizationID uint, id uint, preloads ...string) (models.Championship, error) {
+	var championship models.Championship
+	err := preload(locked(r.db, r.lock), preloads).Where("organization_id = ?", organizationID).First(&championship, id).Error
+	return championship, translate(err)
+}
+
+func (r *gormChampionshipRepository) FindByIDs(organizationID uint, ids []uint) ([]models.Championship, error) {
+	var championships []models.Championship
+	err := locked(r.db, r.lock).Where("organization_id = ? AND id IN ?", organizationID, ids).Order("id").Find(&championships).Error
+	return championships, err
+}
+
+func (r *gormChampionshipRepository) Create(championship *models.Championship) error {
+	return r.db.Create(championship).Error
+}
+
+func (r *gormChampionshipRepository) Save(championship *models.Championship) error {
+	return r.db.Omit("Players", "Matches", "Seeds").Save(championship).Error
+}
+
+func (r *gormChampionshipRepository) Delete(championship *models.Championship) error {
+	return r.db.Delete(championship).Error
+}
+
+func (r *gormChampionshipRepository) UpdateStatus(id uint, status models.ChampionshipStatus) error {
+	return r.db.Model(&models.Championship{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":  status,
+		"version": nextVersion,
+	}).Error
+}
+
+func (r *gormChampionshipRepository) AddMember(member *models.ChampionshipMember) error {
+	return r.db.Create(member).Error
+}
+
+func (r *gormChampionshipRepository) InSeason(seasonID uint) ([]models.Championship, error) {
+	var championships []models.Championship
+	err := r.db.Preload("Players").Where("season_id = ?", seasonID).Order("created_at ASC, id ASC").Find(&championships).Error
+	return championships, err
+}
+
+func (r *gormChampionshipRepository) CountPlayers(id uint) (int64, error) {
+	var count int64
+	err := r.db.Table("player_championships").Where("championship_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *gormChampionshipRepository) Players(id uint) ([]models.Player, error) {
+	var players []models.Player
+	err := r.db.Joins("JOIN player_championships ON players.id = player_championships.player_id").
+		Where("player_championships.championship_id = ?", id).
+		Find(&players).Error
+	return players, err
+}
+
+func (r *gormChampionshipRepository) SaveDraw(id uint, method string, seed *int64, playerIDs []uint) error {
+	if err := r.db.Where("championship_id = ?", id).Delete(&models.ChampionshipSeed{}).Error; err != nil {
+		return err
+	}
+
+	seeds := make([]models.ChampionshipSeed, 0, len(playerIDs))
+	for i, playerID := range playerIDs {
+		seeds = append(seeds, models.ChampionshipSeed{ChampionshipID: id, PlayerID: playerID, Seed: i + 1})
+	}
+	if len(seeds) > 0 {
+		if err := r.db.Create(&seeds).Error; err != nil {
+			return err
+		}
+	}
+
+	return r.db.Model(&models.Championship{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"seeding_method": method,
+		"draw_seed":      seed,
+		"version":        nextVersion,
+	}).Error
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor is returned for a cursor that was not issued for the list
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SortError is returned for a sort field a list does not support
+type SortError struct {
+	Fields []string
+}
+
+func (e *SortError) Error() string {
+	return "Sort must be one of " + strings.Join(e.Fields, ", ") + ", prefixed with - for descending order"
+}
+
+// ListOptions sort and page a list
+type ListOptions struct {
+	// Field to sort by, prefixed with "-" for descending order. Ties are
+	// broken by ID. Empty for the list's default order.
+	Sort string
+	// Maximum number of rows to return, 0 for all
+	Limit int
+	// Next of the previous page, empty for the first page
+	Cursor string
+}
+
+// Page describes the rows a list returned
+type Page struct {
+	// Number of rows matching the filters, on all pages
+	Total int64
+	// Cursor of the next page, empty on the last page
+	Next string
+}
+
+// sortKey is a field a list can be sorted by
+type sortKey[T any] struct {
+	// Column or SQL expression
+	column   string
+	nullable bool
+	// value returns the key of a row, nil if it is NULL
+	value func(item T) interface{}
+	// parse restores a key from a cursor
+	parse func(raw json.RawMessage) (interface{}, error)
+}
+
+func timeKey[T any](column string, nullable bool, value func(item T) *time.Time) sortKey[T] {
+	return sortKey[T]{
+		column:   column,
+		nullable: nullable,
+		value: func(item T) interface{} {
+			if t := value(item); t != nil {
+				return *t
+			}
+			return nil
+		},
+		parse: func(raw json.RawMessage) (interface{}, error) {
+			var t time.Time
+			err := json.Unmarshal(raw, &t)
+			return t, err
+		},
+	}
+}
+
+func stringKey[T any](column string, value func(item T) string) sortKey[T] {
+	return sortKey[T]{
+		column: column,
+		value:  func(item T) interface{} { return value(item) },
+		parse: func(raw json.RawMessage) (interface{}, error) {
+			var s string
+			err := json.Unmarshal(raw, &s)
+			return s, err
+		},
+	}
+}
+
+func idKey[T any](column string, value func(item T) uint) sortKey[T] {
+	return sortKey[T]{
+		column: column,
+		value:  func(item T) interface{} { return value(item) },
+		parse: func(raw json.RawMessage) (interface{}, error) {
+			var id uint
+			err := json.Unmarshal(raw, &id)
+			return id, err
+		},
+	}
+}
+
+// cursor is the position of the last row of a page
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+// listing describes how the rows of a list are sorted and loaded
+type listing[T any] struct {
+	keys        map[string]sortKey[T]
+	defaultSort string
+	// Qualified ID column, which breaks ties
+	idColumn string
+	id       func(item T) uint
+	preloads []string
+}
+
+// Sorting describes the orders a list supports
+type Sorting struct {
+	Fields  []string
+	Default string
+}
+
+// Orders of the lists, as documented in the API specification
+var (
+	ChampionshipSorting = championshipListing.sorting()
+	PlayerSorting       = playerListing.sorting()
+	MatchSorting        = matchListing.sorting()
+)
+
+func (l listing[T]) sorting() Sorting {
+	fields := make([]string, 0, len(l.keys))
+	for field := range l.keys {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return Sorting{Fields: fields, Default: l.defaultSort}
+}
+
+// list counts the rows of query, then loads the page selected by options.
+// Rows are ordered by the sort key and their ID, NULL keys last, and pages
+// continue after the row a cursor points to, so rows inserted meanwhile
+// neither shift nor repeat later pages.
+func (l listing[T]) list(query *gorm.DB, options ListOptions) ([]T, Page, error) {
+	var page Page
+
+	sort := options.Sort
+	if sort == "" {
+		sort = l.defaultSort
+	}
+	descending := strings.HasPrefix(sort, "-")
+	key, ok := l.keys[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		return nil, page, &SortError{Fields: l.sorting().Fields}
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&page.Total).Error; err != nil {
+		return nil, page, err
+	}
+
+	direction, after := "ASC", ">"
+	if descending {
+		direction, after = "DESC", "<"
+	}
+
+	if options.Cursor != "" {
+		position, value, err := decodeCursor(options.Cursor, sort, key)
+		if err != nil {
+			return nil, page, err
+		}
+
+		switch {
+		case value == nil:
+			query = query.Where(fmt.Sprintf("(%s IS NULL AND %s %s ?)", key.column, l.idColumn, after), position.ID)
+		case key.nullable:
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?) OR %s IS NULL)",
+				key.column, after, key.column, l.idColumn, after, key.column), value, value, position.ID)
+		default:
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s %s ?))",
+				key.column, after, key.column, l.idColumn, after), value, value, position.ID)
+		}
+	}
+
+	if key.nullable {
+		query = query.Order(key.column + " IS NULL")
+	}
+	query = query.Order(key.column + " " + direction).Order(l.idColumn + " " + direction)
+
+	// One more row than requested tells whether there is a next page
+	if options.Limit > 0 {
+		query = query.Limit(options.Limit + 1)
+	}
+
+	var items []T
+	if err := preload(query, l.preloads).Find(&items).Error; err != nil {
+		return nil, page, err
+	}
+
+	if options.Limit > 0 && len(items) > options.Limit {
+		items = items[:options.Limit]
+		last := items[len(items)-1]
+		next, err := encodeCursor(sort, key.value(last), l.id(last))
+		if err != nil {
+			return nil, page, err
+		}
+		page.Next = next
+	}
+
+	return items, page, nil
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern, for use with ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func encodeCursor(sort string, value interface{}, id uint) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(cursor{Sort: sort, Value: raw, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor reads a cursor, which is only valid for the order it was issued for
+func decodeCursor[T any](encoded string, sort string, key sortKey[T]) (cursor, interface{}, error) {
+	var position cursor
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return position, nil, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &position); err != nil || position.Sort != sort {
+		return position, nil, ErrInvalidCursor
+	}
+
+	if string(position.Value) == "null" {
+		if !key.nullable {
+			return position, nil, ErrInvalidCursor
+		}
+		return position, nil, nil
+	}
+
+	value, err := key.parse(position.Value)
+	if err != nil {
+		return position, nil, ErrInvalidCursor
+	}
+	return position, value, nil
+}
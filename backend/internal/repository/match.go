@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MatchRepository interface {
+	// List returns the matches of the organization that pass the filter,
+	// newest first unless sorted otherwise, with their championship preloaded
+	List(organizationID uint, filter MatchFilter, options ListOptions) ([]models.Match, Page, error)
+	// Get loads a match of the organization with the given associations
+	Get(organizationID uint, id uint, preloads ...string) (models.Match, error)
+	Create(match *models.Match) error
+	CreateAll(matches []models.Match) error
+	Save(match *models.Match) error
+	Delete(match *models.Match) error
+	// Finished returns every finished match of the organization
+	Finished(organizationID uint) ([]models.Match, error)
+	// FinishedInChampionship returns the finished matches of a championship in
+	// the order they were finished, optionally only up to a point in time
+	FinishedInChampionship(championshipID uint, until *time.Time) ([]models.Match, error)
+	// Results returns the finished matches of the organization that pass the
+	// filter, in the order they were finished
+	Results(organizationID uint, filter ResultFilter) ([]models.Match, error)
+	// LatestFinished returns the number of finished matches of the
+	// organization and the one updated last, a zero match if there is none
+	LatestFinished(organizationID uint) (int64, models.Match, error)
+	// Scheduled returns the scheduled matches of the organization that pass
+	// the filter, in the order they are scheduled
+	Scheduled(organizationID uint, filter ScheduleFilter) ([]models.Match, error)
+	// InChampionship returns every match of a championship, by ID
+	InChampionship(championshipID uint) ([]models.Match, error)
+	// WithStatus returns the matches of a championship in a status, by ID
+	WithStatus(championshipID uint, status models.MatchStatus) ([]models.Match, error)
+	CountInChampionship(championshipID uint) (int64, error)
+	SetScheduledAt(id uint, scheduledAt time.Time) error
+}
+
+// MatchFilter selects matches, zero fields select all
+type MatchFilter struct {
+	ChampionshipID *uint
+	Status         models.MatchStatus
+	// Name or ID of a player taking part in the match
+	Player   string
+	PlayerID *uint
+	Game     string
+	// Inclusive bounds of the match date, see matchDate
+	From *time.Time
+	To   *time.Time
+}
+
+// ResultFilter selects finished matches, zero fields select all
+type ResultFilter struct {
+	ChampionshipID *uint
+	// Matches of the player, or between the player and the opponent if set
+	Player   string
+	Opponent string
+	// Inclusive bounds of the time a match was finished
+	From *time.Time
+	To   *time.Time
+}
+
+// ScheduleFilter selects scheduled matches, zero fields select all
+type ScheduleFilter struct {
+	ChampionshipID *uint
+	// Matches the player takes part in
+	Player string
+}
+
+// matchDate is when a match was finished, else when it is scheduled, else
+// when it was created
+const matchDate = "COALESCE(matches.finished_at, matches.scheduled_at, matches.created_at)"
+
+var matchListing = listing[models.Match]{
+	keys: map[string]sortKey[models.Match]{
+		"id":         idKey("matches.id", func(m models.Match) uint { return m.ID }),
+		"created_at": timeKey("matches.created_at", false, func(m models.Match) *time.Time { return &m.CreatedAt }),
+		"date": timeKey(matchDate, false, func(m models.Match) *time.Time {
+			if m.FinishedAt != nil {
+				return m.FinishedAt
+			}
+			if m.ScheduledAt != nil {
+				return m.ScheduledAt
+			}
+			return &m.CreatedAt
+		}),
+		"scheduled_at": timeKey("matches.scheduled_at", true, func(m models.Match) *time.Time { return m.ScheduledAt }),
+		"finished_at":  timeKey("matches.finished_at", true, func(m models.Match) *time.Time { return m.FinishedAt }),
+		"game":         stringKey("matches.game", func(m models.Match) string { return m.Game }),
+		"status":       stringKey("matches.status", func(m models.Match) string { return string(m.Status) }),
+	},
+	defaultSort: "-created_at",
+	idColumn:    "matches.id",
+	id:          func(m models.Match) uint { return m.ID },
+	preloads:    []string{"Championship"},
+}
+
+type gormMatchRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormMatchRepository) List(organizationID uint, filter MatchFilter, options ListOptions) ([]models.Match, Page, error) {
+	query := r.db.Model(&models.Match{}).Where("matches.organization_id = ?", organizationID)
+	if filter.ChampionshipID != nil {
+		query = query.Where("matches.championship_id = ?", *filter.ChampionshipID)
+	}
+	if filter.Status != "" {
+		query = query.Where("matches.status = ?", filter.Status)
+	}
+	if filter.Player != "" {
+		query = query.Where("(matches.player1 = ? OR matches.player2 = ?)", filter.Player, filter.Player)
+	}
+	if filter.PlayerID != nil {
+		names := r.db.Model(&models.Player{}).Select("name").Where("id = ? AND organization_id = ?", *filter.PlayerID, organizationID)
+		query = query.Where("(matches.player1 IN (?) OR matches.player2 IN (?))", names, names)
+	}
+	if filter.Game != "" {
+		query = query.Where("matches.game = ?", filter.Game)
+	}
+	if filter.From != nil {
+		query = query.Where(matchDate+" >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where(matchDate+" <= ?", *filter.To)
+	}
+
+	return matchListing.list(query, options)
+}
+
+func (r *gormMatchRepository) Get(organizationID uint, id uint, preloads ...string) (models.Match, error) {
+	var match models.Match
+	err := preload(locked(r.db, r.lock), preloads).Where("organization_id = ?", organizationID).First(&match, id).Error
+	return match, translate(err)
+}
+
+func (r *gormMatchRepository) Create(match *models.Match) error {
+	return r.db.Create(match).Error
+}
+
+func (r *gormMatchRepository) CreateAll(matches []models.Match) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	return r.db.Create(&matches).Error
+}
+
+func (r *gormMatchRepository) Save(match *models.Match) error {
+	return r.db.Omit("Championship").Save(match).Error
+}
+
+func (r *gormMatchRepository) Delete(match *models.Match) error {
+	return r.db.Delete(match).Error
+}
+
+func (r *gormMatchRepository) Finished(organizationID uint) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.Where("organization_id = ? AND status = ?", organizationID, models.MatchStatusFinished).Find(&matches).Error
+	return matches, err
+}
+
+func (r *gormMatchRepository) FinishedInChampionship(championshipID uint, until *time.Time) ([]models.Match, error) {
+	query := r.db.Where("championship_id = ? AND status = ?", championshipID, models.MatchStatusFinished)
+	if until != nil {
+		query = query.Where("finished_at <= ?", *until)
+	}
+
+	var matches []models.Match
+	err := query.Order("finished_at ASC, id ASC").Find(&matches).Error
+	return matches, err
+}
+
+func (r *gormMatchRepository) Results(organizationID uint, filter ResultFilter) ([]models.Match, error) {
+	query := r.db.Where("organization_id = ? AND status = ?", organizationID, models.MatchStatusFinished)
+	if filter.ChampionshipID != nil {
+		query = query.Where("championship_id = ?", *filter.ChampionshipID)
+	}
+	switch {
+	case filter.Player != "" && filter.Opponent != "":
+		query = query.Where("(player1 = ? AND player2 = ?) OR (player1 = ? AND player2 = ?)",
+			filter.Player, filter.Opponent, filter.Opponent, filter.Player)
+	case filter.Player != "":
+		query = query.Where("player1 = ? OR player2 = ?", filter.Player, filter.Player)
+	}
+	if filter.From != nil {
+		query = query.Where("finished_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("finished_at <= ?", *filter.To)
+	}
+
+	var matches []models.Match
+	err := query.Order("finished_at ASC, id ASC").Find(&matches).Error
+	return matches, err
+}
+
+func (r *gormMatchRepository) LatestFinished(organizationID uint) (int64, models.Match, error) {
+	var count int64
+	var latest models.Match
+	finished := func() *gorm.DB {
+		return r.db.Model(&models.Match{}).Where("organization_id = ? AND status = ?", organizationID, models.MatchStatusFinished)
+	}
+	if err := finished().Count(&count).Error; err != nil {
+		return 0, latest, err
+	}
+	err := finished().Order("updated_at DESC, id DESC").Limit(1).Find(&latest).Error
+	return count, latest, err
+}
+
+func (r *gormMatchRepository) Scheduled(organizationID uint, filter ScheduleFilter) ([]models.Match, error) {
+	query := r.db.Where("organization_id = ? AND scheduled_at IS NOT NULL", organizationID)
+	if filter.ChampionshipID != nil {
+		query = query.Where("championship_id = ?", *filter.ChampionshipID)
+	}
+	if filter.Player != "" {
+		query = query.Where("(player1 = ? OR player2 = ?)", filter.Player, filter.Player)
+	}
+
+	var matches []models.Match
+	err := query.Order("scheduled_at ASC, id ASC").Find(&matches).Error
+	return matches, err
+}
+
+func (r *gormMatchRepository) InChampionship(championshipID uint) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.Where("championship_id = ?", championshipID).Order("id ASC").Find(&matches).Error
+	return matches, err
+}
+
+func (r *gormMatchRepository) WithStatus(championshipID uint, status models.MatchStatus) ([]models.Match, error) {
+	var matches []models.Match
+	err := r.db.Where("championship_id = ? AND status = ?", championshipID, status).Order("id ASC").Find(&matches).Error
+	return matches, err
+}
+
+func (r *gormMatchRepository) CountInChampionship(championshipID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Match{}).Where("championship_id = ?", championshipID).Count(&count).Error
+	return count, err
+}
+
+func (r *gormMatchRepository) SetScheduledAt(id uint, scheduledAt time.Time) error {
+	return r.db.Model(&models.Match{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"scheduled_at": scheduledAt,
+		"version":      nextVersion,
+	}).Error
+}
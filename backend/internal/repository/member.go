@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ChampionshipMemberRepository interface {
+	// List returns the members of a championship with their users, oldest first
+	List(championshipID uint) ([]models.ChampionshipMember, error)
+	// Get loads the membership of a user in a championship with the user
+	Get(championshipID uint, userID uint) (models.ChampionshipMember, error)
+	// Owners returns the owners of a championship. Within a transaction they
+	// stay locked, so the last owner cannot be removed by two requests at once.
+	Owners(championshipID uint) ([]models.ChampionshipMember, error)
+	Save(member *models.ChampionshipMember) error
+	Delete(member *models.ChampionshipMember) error
+}
+
+type gormChampionshipMemberRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormChampionshipMemberRepository) List(championshipID uint) ([]models.ChampionshipMember, error) {
+	var members []models.ChampionshipMember
+	err := r.db.Preload("User").Where("championship_id = ?", championshipID).Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *gormChampionshipMemberRepository) Get(championshipID uint, userID uint) (models.ChampionshipMember, error) {
+	var member models.ChampionshipMember
+	err := locked(r.db, r.lock).Preload("User").Where("championship_id = ? AND user_id = ?", championshipID, userID).First(&member).Error
+	return member, translate(err)
+}
+
+func (r *gormChampionshipMemberRepository) Owners(championshipID uint) ([]models.ChampionshipMember, error) {
+	var owners []models.ChampionshipMember
+	err := locked(r.db, r.lock).Where("championship_id = ? AND role = ?", championshipID, models.ChampionshipRoleOwner).Find(&owners).Error
+	return owners, err
+}
+
+func (r *gormChampionshipMemberRepository) Save(member *models.ChampionshipMember) error {
+	return r.db.Omit("User").Save(member).Error
+}
+
+func (r *gormChampionshipMemberRepository) Delete(member *models.ChampionshipMember) error {
+	return r.db.Delete(member).Error
+}
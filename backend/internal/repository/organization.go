@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	// Memberships returns the organizations of a user with their
+	// memberships, oldest first
+	Memberships(userID uint) ([]models.OrganizationMember, error)
+	Create(organization *models.Organization) error
+	// Members returns the members of an organization with their users,
+	// oldest first
+	Members(organizationID uint) ([]models.OrganizationMember, error)
+	// GetMember loads the membership of a user in an organization with the user
+	GetMember(organizationID uint, userID uint) (models.OrganizationMember, error)
+	// Owners returns the owners of an organization. Within a transaction they
+	// stay locked, so the last owner cannot be removed by two requests at once.
+	Owners(organizationID uint) ([]models.OrganizationMember, error)
+	SaveMember(member *models.OrganizationMember) error
+	// DeleteMember removes a member along with the member's roles in the
+	// championships of the organization
+	DeleteMember(member *models.OrganizationMember) error
+}
+
+type gormOrganizationRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormOrganizationRepository) Memberships(userID uint) ([]models.OrganizationMember, error) {
+	var memberships []models.OrganizationMember
+	err := r.db.Preload("Organization").Where("user_id = ?", userID).Order("created_at ASC").Find(&memberships).Error
+	return memberships, err
+}
+
+func (r *gormOrganizationRepository) Create(organization *models.Organization) error {
+	return r.db.Create(organization).Error
+}
+
+func (r *gormOrganizationRepository) Members(organizationID uint) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	err := r.db.Preload("User").Where("organization_id = ?", organizationID).Order("created_at ASC").Find(&members).Error
+	return members, err
+}
+
+func (r *gormOrganizationRepository) GetMember(organizationID uint, userID uint) (models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	err := locked(r.db, r.lock).Preload("User").Where("organization_id = ? AND user_id = ?", organizationID, userID).First(&member).Error
+	return member, translate(err)
+}
+
+func (r *gormOrganizationRepository) Owners(organizationID uint) ([]models.OrganizationMember, error) {
+	var owners []models.OrganizationMember
+	err := locked(r.db, r.lock).Where("organization_id = ? AND role = ?", organizationID, models.OrganizationRoleOwner).Find(&owners).Error
+	return owners, err
+}
+
+func (r *gormOrganizationRepository) SaveMember(member *models.OrganizationMember) error {
+	return r.db.Omit("Organization", "User").Save(member).Error
+}
+
+func (r *gormOrganizationRepository) DeleteMember(member *models.OrganizationMember) error {
+	if err := r.db.Where("user_id = ? AND championship_id IN (?)", member.UserID,
+		r.db.Model(&models.Championship{}).Select("id").Where("organization_id = ?", member.OrganizationID)).
+		Delete(&models.ChampionshipMember{}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(member).Error
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type PlayerRepository interface {
+	// List returns the players of the organization that pass the filter,
+	// newest first unless sorted otherwise, with their championships preloaded
+	List(organizationID uint, filter PlayerFilter, options ListOptions) ([]models.Player, Page, error)
+	// Get loads a player of the organization with its championships
+	Get(organizationID uint, id uint) (models.Player, error)
+	FindByName(organizationID uint, name string) (models.Player, error)
+	Create(player *models.Player) error
+	UpdateName(id uint, name string) error
+	// SetChampionships replaces the championships the player belongs to
+	SetChampionships(id uint, championshipIDs []uint) error
+	Delete(player *models.Player) error
+	InChampionship(id uint, championshipID uint) (bool, error)
+	// Unavailability returns the unavailability of the players, in the order
+	// it was added
+	Unavailability(ids []uint) ([]models.PlayerUnavailability, error)
+	// GetUnavailability loads an unavailability of the player
+	GetUnavailability(id uint, unavailabilityID uint) (models.PlayerUnavailability, error)
+	CreateUnavailability(unavailability *models.PlayerUnavailability) error
+	DeleteUnavailability(unavailability *models.PlayerUnavailability) error
+	// Achievements returns the badges the player earned with the matches that
+	// earned them, in the order they were awarded
+	Achievements(id uint) ([]models.Achievement, error)
+}
+
+// PlayerFilter selects players, zero fields select all
+type PlayerFilter struct {
+	ChampionshipID *uint
+	// Part of the name, in any case
+	Name string
+}
+
+var playerListing = listing[models.Player]{
+	keys: map[string]sortKey[models.Player]{
+		"id":         idKey("players.id", func(p models.Player) uint { return p.ID }),
+		"created_at": timeKey("players.created_at", false, func(p models.Player) *time.Time { return &p.CreatedAt }),
+		"name":       stringKey("players.name", func(p models.Player) string { return p.Name }),
+	},
+	defaultSort: "-created_at",
+	idColumn:    "players.id",
+	id:          func(p models.Player) uint { return p.ID },
+	preloads:    []string{"Championships"},
+}
+
+type gormPlayerRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormPlayerRepository) List(organizationID uint, filter PlayerFilter, options ListOptions) ([]models.Player, Page, error) {
+	query := r.db.Model(&models.Player{}).Where("players.organization_id = ?", organizationID)
+	if filter.ChampionshipID != nil {
+		// Filter players by championship using the join table
+		query = query.Joins("JOIN player_championships ON players.id = player_championships.player_id").
+			Where("player_championships.championship_id = ?", *filter.ChampionshipID)
+	}
+	if filter.Name != "" {
+		query = query.Where(`LOWER(players.name) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(filter.Name))+"%")
+	}
+
+	return playerListing.list(query, options)
+}
+
+func (r *gormPlayerRepository) Get(organizationID uint, id uint) (models.Player, error) {
+	var player models.Player
+	err := locked(r.db, r.lock).Where("organization_id = ?", organizationID).Preload("Championships").First(&player, id).Error
+	return player, translate(err)
+}
+
+func (r *gormPlayerRepository) FindByName(organizationID uint, name string) (models.Player, error) {
+	var player models.Player
+	err := r.db.Where("organization_id = ? AND name = ?", organizationID, name).First(&player).Error
+	return player, translate(err)
+}
+
+func (r *gormPlayerRepository) Create(player *models.Player) error {
+	return r.db.Create(player).Error
+}
+
+func (r *gormPlayerRepository) UpdateName(id uint, name string) error {
+	return r.db.Model(&models.Player{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"name":    name,
+		"version": nextVersion,
+	}).Error
+}
+
+func (r *gormPlayerRepository) SetChampionships(id uint, championshipIDs []uint) error {
+	if err := r.db.Model(&models.Player{}).Where("id = ?", id).Update("version", nextVersion).Error; err != nil {
+		return err
+	}
+	if err := r.db.Table("player_championships").Where("player_id = ?", id).Delete(nil).Error; err != nil {
+		return err
+	}
+
+	seen := make(map[uint]bool, len(championshipIDs))
+	for _, championshipID := range championshipIDs {
+		if seen[championshipID] {
+			continue
+		}
+		seen[championshipID] = true
+
+		if err := r.db.Table("player_championships").Create(map[string]interface{}{
+			"player_id":       id,
+			"championship_id": championshipID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *gormPlayerRepository) Delete(player *models.Player) error {
+	return r.db.Delete(player).Error
+}
+
+func (r *gormPlayerRepository) InChampionship(id uint, championshipID uint) (bool, error) {
+	var count int64
+	err := r.db.Table("player_championships").
+		Where("player_id = ? AND championship_id = ?", id, championshipID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormPlayerRepository) Unavailability(ids []uint) ([]models.PlayerUnavailability, error) {
+	var rules []models.PlayerUnavailability
+	if len(ids) == 0 {
+		return rules, nil
+	}
+	err := r.db.Where("player_id IN ?", ids).Order("created_at ASC, id ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *gormPlayerRepository) GetUnavailability(id uint, unavailabilityID uint) (models.PlayerUnavailability, error) {
+	var unavailability models.PlayerUnavailability
+	err := r.db.Where("player_id = ?", id).First(&unavailability, unavailabilityID).Error
+	return unavailability, translate(err)
+}
+
+func (r *gormPlayerRepository) CreateUnavailability(unavailability *models.PlayerUnavailability) error {
+	return r.db.Create(unavailability).Error
+}
+
+func (r *gormPlayerRepository) DeleteUnavailability(unavailability *models.PlayerUnavailability) error {
+	return r.db.Delete(unavailability).Error
+}
+
+func (r *gormPlayerRepository) Achievements(id uint) ([]models.Achievement, error) {
+	var earned []models.Achievement
+	err := r.db.Preload("Match").Where("player_id = ?", id).Order("awarded_at ASC, id ASC").Find(&earned).Error
+	return earned, err
+}
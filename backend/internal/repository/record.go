@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RecordRepository interface {
+	// List returns the records of the organization, each with the match
+	// that set it
+	List(organizationID uint) ([]models.Record, error)
+}
+
+type gormRecordRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormRecordRepository) List(organizationID uint) ([]models.Record, error) {
+	var records []models.Record
+	err := r.db.Preload("Match").Where("organization_id = ?", organizationID).Find(&records).Error
+	return records, err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNotFound is returned when a record does not exist in the organization
+var ErrNotFound = errors.New("record not found")
+
+// Store gives access to all repositories
+type Store interface {
+	Championships() ChampionshipRepository
+	Players() PlayerRepository
+	Matches() MatchRepository
+	Seasons() SeasonRepository
+	ChampionshipMembers() ChampionshipMemberRepository
+	APIKeys() APIKeyRepository
+	Organizations() OrganizationRepository
+	Users() UserRepository
+	Records() RecordRepository
+
+	// Transaction runs fn with repositories that share one database
+	// transaction, which is committed if fn returns nil. Rows returned by Get
+	// and FindByIDs in the transaction stay locked until it ends, so checks
+	// made on them still hold when fn writes.
+	Transaction(fn func(Store) error) error
+}
+
+type gormStore struct {
+	db *gorm.DB
+	// Lock rows that are read, set within transactions
+	lock bool
+}
+
+// NewStore returns a Store backed by GORM
+func NewStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Championships() ChampionshipRepository {
+	return &gormChampionshipRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Players() PlayerRepository {
+	return &gormPlayerRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Matches() MatchRepository {
+	return &gormMatchRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Seasons() SeasonRepository {
+	return &gormSeasonRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) ChampionshipMembers() ChampionshipMemberRepository {
+	return &gormChampionshipMemberRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) APIKeys() APIKeyRepository {
+	return &gormAPIKeyRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Organizations() OrganizationRepository {
+	return &gormOrganizationRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Users() UserRepository {
+	return &gormUserRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Records() RecordRepository {
+	return &gormRecordRepository{db: s.db, lock: s.lock}
+}
+
+func (s *gormStore) Transaction(fn func(Store) error) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&gormStore{db: tx, lock: true})
+	})
+}
+
+// translate maps GORM errors to repository errors
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// locked adds SELECT ... FOR UPDATE to a query if rows should be locked.
+// Databases without row locks, like SQLite, serialize transactions instead.
+func locked(db *gorm.DB, lock bool) *gorm.DB {
+	if lock {
+		return db.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	return db
+}
+
+// nextVersion increments the version of the updated rows
+var nextVersion = gorm.Expr("version + 1")
+
+// preload applies the given associations to a query
+func preload(db *gorm.DB, associations []string) *gorm.DB {
+	for _, association := range associations {
+		db = db.Preload(association)
+	}
+	return db
+}
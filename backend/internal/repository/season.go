@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SeasonRepository interface {
+	// List returns the seasons of the organization, newest first
+	List(organizationID uint) ([]models.Season, error)
+	// Get loads a season of the organization with the given associations
+	Get(organizationID uint, id uint, preloads ...string) (models.Season, error)
+	Create(season *models.Season) error
+	Save(season *models.Season) error
+	// Delete removes a season. Its championships stay, without a season.
+	Delete(season *models.Season) error
+}
+
+type gormSeasonRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormSeasonRepository) List(organizationID uint) ([]models.Season, error) {
+	var seasons []models.Season
+	err := r.db.Where("organization_id = ?", organizationID).Order("created_at DESC, id DESC").Find(&seasons).Error
+	return seasons, err
+}
+
+func (r *gormSeasonRepository) Get(organizationID uint, id uint, preloads ...string) (models.Season, error) {
+	var season models.Season
+	err := preload(locked(r.db, r.lock), preloads).Where("organization_id = ?", organizationID).First(&season, id).Error
+	return season, translate(err)
+}
+
+func (r *gormSeasonRepository) Create(season *models.Season) error {
+	return r.db.Create(season).Error
+}
+
+func (r *gormSeasonRepository) Save(season *models.Season) error {
+	return r.db.Omit("Championships").Save(season).Error
+}
+
+func (r *gormSeasonRepository) Delete(season *models.Season) error {
+	if err := r.db.Model(&models.Championship{}).Where("season_id = ?", season.ID).Updates(map[string]interface{}{
+		"season_id":     nil,
+		"season_weight": 1,
+		"version":       nextVersion,
+	}).Error; err != nil {
+		return err
+	}
+	return r.db.Delete(season).Error
+}
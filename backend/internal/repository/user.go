@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"scoretracker/backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type UserRepository interface {
+	Get(id uint) (models.User, error)
+	// FindByEmail looks up a user by the normalized email address
+	FindByEmail(email string) (models.User, error)
+	// LinkedTo returns the users linked to a player
+	LinkedTo(playerID uint) ([]models.User, error)
+	// LinkPlayer links a user to a player, replacing any player it was
+	// linked to before
+	LinkPlayer(id uint, playerID uint) error
+	// UnlinkPlayer removes the link of the player's user and reports whether
+	// there was one
+	UnlinkPlayer(playerID uint) (bool, error)
+}
+
+type gormUserRepository struct {
+	db   *gorm.DB
+	lock bool
+}
+
+func (r *gormUserRepository) Get(id uint) (models.User, error) {
+	var user models.User
+	err := locked(r.db, r.lock).Preload("Player").First(&user, id).Error
+	return user, translate(err)
+}
+
+func (r *gormUserRepository) FindByEmail(email string) (models.User, error) {
+	var user models.User
+	err := locked(r.db, r.lock).Where("email = ?", email).First(&user).Error
+	return user, translate(err)
+}
+
+func (r *gormUserRepository) LinkedTo(playerID uint) ([]models.User, error) {
+	var users []models.User
+	err := locked(r.db, r.lock).Where("player_id = ?", playerID).Find(&users).Error
+	return users, err
+}
+
+func (r *gormUserRepository) LinkPlayer(id uint, playerID uint) error {
+	return r.db.Model(&models.User{}).Where("id = ?", id).Update("player_id", playerID).Error
+}
+
+func (r *gormUserRepository) UnlinkPlayer(playerID uint) (bool, error) {
+	result := r.db.Model(&models.User{}).Where("player_id = ?", playerID).Update("player_id", nil)
+	return result.RowsAffected > 0, result.Error
+}
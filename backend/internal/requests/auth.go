@@ -0,0 +1,41 @@
+package requests
+
+import "encoding/json"
+
+type Register struct {
+	Email string `json:"email" binding:"required,email,max=254"`
+	// bcrypt only uses the first 72 bytes
+	Password string `json:"password" binding:"required,min=8,max=72" trim:"false"`
+}
+
+type Login struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required" trim:"false"`
+}
+
+// RefreshToken is the body of refreshing tokens and of logging out
+type RefreshToken struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type UpdateUser struct {
+	// A null player_id unlinks the player, a missing one keeps the link
+	PlayerID *uint `json:"player_id"`
+	// Whether player_id was sent, as a nil PlayerID may mean either
+	PlayerIDSet bool `json:"-"`
+}
+
+func (u *UpdateUser) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	// Without the methods of UpdateUser, so this does not recurse
+	type fields UpdateUser
+	if err := json.Unmarshal(data, (*fields)(u)); err != nil {
+		return err
+	}
+	_, u.PlayerIDSet = members["player_id"]
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package requests holds the bodies the endpoints accept. Handlers never bind
+// into models, so clients can only send the fields a request declares.
+//
+// Bind decodes a body strictly: members the request does not declare are
+// rejected, strings are trimmed unless tagged trim:"false", and the rules of
+// the binding tags are validated afterwards.
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"scoretracker/backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// serverFields are set by the server only. Sending them is rejected as
+// read_only rather than unknown_field, which tells clients that send back
+// whole records what to leave out.
+var serverFields = map[string]bool{
+	"id":              true,
+	"organization_id": true,
+	"status":          true,
+	"winner":          true,
+	"version":         true,
+	"created_at":      true,
+	"updated_at":      true,
+	"started_at":      true,
+	"finished_at":     true,
+	"reported_by":     true,
+	"reported_at":     true,
+	"season_id":       true,
+	"season_weight":   true,
+	"seeding_method":  true,
+	"draw_seed":       true,
+}
+
+var errNotObject = errors.New("request body must be a JSON object")
+
+// Bind decodes the JSON body of the request into request, which must be a
+// pointer to a struct, and validates it
+func Bind(c *gin.Context, request interface{}) error {
+	if c.Request.Body == nil {
+		return io.EOF
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return io.EOF
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil {
+		return err
+	}
+	if members == nil {
+		return errNotObject
+	}
+
+	value := reflect.ValueOf(request).Elem()
+	if err := checkMembers(members, value.Type()); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, request); err != nil {
+		return err
+	}
+
+	trim(value)
+	return binding.Validator.ValidateStruct(request)
+}
+
+// checkMembers rejects members that are not fields of the request. Unlike
+// encoding/json, names must match exactly.
+func checkMembers(members map[string]json.RawMessage, t reflect.Type) error {
+	fields := jsonFields(t)
+
+	var errs problem.FieldErrors
+	for name := range members {
+		switch {
+		case fields[name]:
+		case serverFields[name]:
+			errs = append(errs, problem.FieldError{Field: name, Code: problem.FieldReadOnly, Message: name + " is set by the server and cannot be sent"})
+		default:
+			errs = append(errs, problem.FieldError{Field: name, Code: problem.FieldUnknown, Message: name + " is not a known field"})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+// jsonFields returns the JSON names of the fields of a struct
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+// trim removes surrounding whitespace from the strings of a struct
+func trim(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		tag := value.Type().Field(i)
+		if !tag.IsExported() || tag.Tag.Get("trim") == "false" {
+			continue
+		}
+
+		if field.Kind() == reflect.Pointer && !field.IsNil() {
+			field = field.Elem()
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(strings.TrimSpace(field.String()))
+		case reflect.Struct:
+			if tag.Anonymous {
+				trim(field)
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package requests
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/models"
+)
+
+type CreateChampionship struct {
+	Name                     string `json:"name" binding:"required,max=100"`
+	Description              string `json:"description" binding:"max=2000"`
+	RequireConfirmation      bool   `json:"require_confirmation"`
+	ConfirmationTimeoutHours *int   `json:"confirmation_timeout_hours" binding:"omitempty,min=1,max=720"`
+	// Accepted for clients that send whole records, a new championship is
+	// always a draft
+	Status models.ChampionshipStatus `json:"status" binding:"omitempty,eq=draft"`
+}
+
+func (r CreateChampionship) Championship() models.Championship {
+	championship := models.Championship{
+		Name:                r.Name,
+		Description:         r.Description,
+		RequireConfirmation: r.RequireConfirmation,
+		Status:              models.ChampionshipStatusDraft,
+	}
+	if r.ConfirmationTimeoutHours != nil {
+		championship.ConfirmationTimeoutHours = *r.ConfirmationTimeoutHours
+	}
+	return championship
+}
+
+// UpdateChampionship changes the fields present in the body. The status can
+// only be changed by finalizing the championship.
+type UpdateChampionship struct {
+	Name                     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Description              *string `json:"description" binding:"omitempty,max=2000"`
+	RequireConfirmation      *bool   `json:"require_confirmation"`
+	ConfirmationTimeoutHours *int    `json:"confirmation_timeout_hours" binding:"omitempty,min=1,max=720"`
+}
+
+func (r UpdateChampionship) Apply(championship *models.Championship) {
+	if r.Name != nil {
+		championship.Name = *r.Name
+	}
+	if r.Description != nil {
+		championship.Description = *r.Description
+	}
+	if r.RequireConfirmation != nil {
+		championship.RequireConfirmation = *r.RequireConfirmation
+	}
+	if r.ConfirmationTimeoutHours != nil {
+		championship.ConfirmationTimeoutHours = *r.ConfirmationTimeoutHours
+	}
+}
+
+// SetChampionshipMember grants a role to a user
+type SetChampionshipMember struct {
+	UserID uint                    `json:"user_id" binding:"required"`
+	Role   models.ChampionshipRole `json:"role" binding:"required,oneof=owner organizer referee player viewer"`
+}
+
+type CreateAPIKey struct {
+	Name      string             `json:"name" binding:"required,max=100"`
+	Scope     models.APIKeyScope `json:"scope" binding:"required,oneof=read score_entry"`
+	ExpiresAt *time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,74 @@
+package requests
+
+import (
+	"time"
+
+	"scoretracker/backend/internal/models"
+)
+
+type CreateMatch struct {
+	ChampionshipID uint       `json:"championship_id" binding:"required"`
+	Player1        string     `json:"player1" binding:"required,max=100"`
+	Player2        string     `json:"player2" binding:"required,max=100"`
+	Game           string     `json:"game" binding:"max=100"`
+	Player1Score   int        `json:"player1_score" binding:"min=0"`
+	Player2Score   int        `json:"player2_score" binding:"min=0"`
+	ScheduledAt    *time.Time `json:"scheduled_at"`
+	// Accepted for clients that send whole records, a new match is always
+	// pending and has no winner
+	Status models.MatchStatus `json:"status" binding:"omitempty,eq=pending"`
+}
+
+func (r CreateMatch) Match() models.Match {
+	return models.Match{
+		ChampionshipID: r.ChampionshipID,
+		Player1:        r.Player1,
+		Player2:        r.Player2,
+		Game:           r.Game,
+		Status:         models.MatchStatusPending,
+		Player1Score:   r.Player1Score,
+		Player2Score:   r.Player2Score,
+		ScheduledAt:    r.ScheduledAt,
+	}
+}
+
+// CreateMatchV2 is the body of creating a match in version 2 of the API,
+// which refers to the players by ID
+type CreateMatchV2 struct {
+	ChampionshipID uint       `json:"championship_id" binding:"required"`
+	Player1ID      uint       `json:"player1_id" binding:"required"`
+	Player2ID      uint       `json:"player2_id" binding:"required"`
+	Game           string     `json:"game" binding:"max=100"`
+	Player1Score   int        `json:"player1_score" binding:"min=0"`
+	Player2Score   int        `json:"player2_score" binding:"min=0"`
+	ScheduledAt    *time.Time `json:"scheduled_at"`
+}
+
+// CreateMatch is the same request with the names of the players
+func (r CreateMatchV2) CreateMatch(player1 string, player2 string) CreateMatch {
+	return CreateMatch{
+		ChampionshipID: r.ChampionshipID,
+		Player1:        player1,
+		Player2:        player2,
+		Game:           r.Game,
+		Player1Score:   r.Player1Score,
+		Player2Score:   r.Player2Score,
+		ScheduledAt:    r.ScheduledAt,
+	}
+}
+
+// Score is the body of updating the score and of resolving a dispute
+type Score struct {
+	Player1Score int `json:"player1_score" binding:"min=0"`
+	Player2Score int `json:"player2_score" binding:"min=0"`
+}
+
+type Dispute struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type Schedule struct {
+	Slots []time.Time `json:"slots" binding:"required"`
+	// Defaults to one match per slot
+	MatchesPerSlot int `json:"matches_per_slot" binding:"min=0"`
+}
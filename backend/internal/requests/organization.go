@@ -0,0 +1,13 @@
+package requests
+
+import "scoretracker/backend/internal/models"
+
+type CreateOrganization struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// SetOrganizationMember adds a user by email or changes the role of a member
+type SetOrganizationMember struct {
+	Email string                  `json:"email" binding:"required,max=254"`
+	Role  models.OrganizationRole `json:"role" binding:"required,oneof=owner admin member"`
+}
@@ -0,0 +1,29 @@
+package requests
+
+import "scoretracker/backend/internal/models"
+
+type CreatePlayer struct {
+	Name            string `json:"name" binding:"required,max=100"`
+	ChampionshipIDs []uint `json:"championship_ids"`
+}
+
+type UpdatePlayer struct {
+	Name *string `json:"name" binding:"omitempty,min=1,max=100"`
+	// Championships are replaced if the field is present, even if empty
+	ChampionshipIDs []uint `json:"championship_ids"`
+}
+
+// CreateUnavailability needs a weekday for the weekday kind and start and
+// end dates for the date_range kind
+type CreateUnavailability struct {
+	Kind      models.UnavailabilityKind `json:"kind" binding:"required,oneof=weekday date_range"`
+	Weekday   *int                      `json:"weekday" binding:"omitempty,min=0,max=6"`
+	StartDate string                    `json:"start_date"`
+	EndDate   string                    `json:"end_date"`
+	Reason    string                    `json:"reason" binding:"max=500"`
+}
+
+// LinkUser links a member of the organization to the player they play as
+type LinkUser struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
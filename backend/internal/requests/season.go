@@ -0,0 +1,21 @@
+package requests
+
+import "scoretracker/backend/internal/models"
+
+// SaveSeason is the body of creating and of replacing a season
+type SaveSeason struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=2000"`
+	DropWorst   int    `json:"drop_worst" binding:"min=0"`
+}
+
+func (r SaveSeason) Apply(season *models.Season) {
+	season.Name = r.Name
+	season.Description = r.Description
+	season.DropWorst = r.DropWorst
+}
+
+type SeasonChampionship struct {
+	// Defaults to 1
+	Weight *float64 `json:"weight" binding:"omitempty,gt=0"`
+}
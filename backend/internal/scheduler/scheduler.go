@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"scoretracker/backend/internal/models"
+)
+
+// maxSteps bounds the backtracking search so a hopeless request cannot hang the server
+const maxSteps = 200000
+
+type Conflict struct {
+	MatchID uint     `json:"match_id"`
+	Player1 string   `json:"player1"`
+	Player2 string   `json:"player2"`
+	Reasons []string `json:"reasons"`
+}
+
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	parts := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		parts = append(parts, fmt.Sprintf("match %d (%s vs %s): %s",
+			conflict.MatchID, conflict.Player1, conflict.Player2, strings.Join(conflict.Reasons, "; ")))
+	}
+	return "no valid schedule exists: " + strings.Join(parts, ", ")
+}
+
+// Schedule assigns every match to one of the given slots. A slot hosts at most
+// matchesPerSlot matches, a player never plays twice in the same slot and no
+// player is scheduled while unavailable. Unavailability is keyed by player name.
+func Schedule(matches []models.Match, slots []time.Time, matchesPerSlot int, unavailability map[string][]models.PlayerUnavailability) (map[uint]time.Time, error) {
+	if matchesPerSlot < 1 {
+		matchesPerSlot = 1
+	}
+
+	sorted := make([]time.Time, len(slots))
+	copy(sorted, slots)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	if len(matches) > len(sorted)*matchesPerSlot {
+		return nil, &ConflictError{Conflicts: []Conflict{{
+			Reasons: []string{fmt.Sprintf("%d matches do not fit into %d slots with %d matches per slot",
+				len(matches), len(sorted), matchesPerSlot)},
+		}}}
+	}
+
+	// Collect the slots each match can use based on player availability alone
+	candidates := make([][]int, len(matches))
+	var conflicts []Conflict
+	for i, match := range matches {
+		for s, slot := range sorted {
+			if len(blockingRules(match, slot, unavailability)) == 0 {
+				candidates[i] = append(candidates[i], s)
+			}
+		}
+		if len(candidates[i]) == 0 {
+			conflicts = append(conflicts, Conflict{
+				MatchID: match.ID,
+				Player1: match.Player1,
+				Player2: match.Player2,
+				Reasons: unavailabilityReasons(match, sorted, unavailability),
+			})
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Conflicts: conflicts}
+	}
+
+	// Place the most constrained matches first
+	order := make([]int, len(matches))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return len(candidates[order[a]]) < len(candidates[order[b]])
+	})
+
+	s := &search{
+		matches:        matches,
+		candidates:     candidates,
+		order:          order,
+		matchesPerSlot: matchesPerSlot,
+		assigned:       make([]int, len(matches)),
+		slotLoad:       make([]int, len(sorted)),
+		busy:           make([]map[string]bool, len(sorted)),
+	}
+	for i := range s.assigned {
+		s.assigned[i] = -1
+	}
+	for i := range s.busy {
+		s.busy[i] = make(map[string]bool)
+	}
+
+	if !s.place(0) {
+		stuck := matches[order[s.deepest]]
+		reason := fmt.Sprintf("all %d slots where both players are available are already full or used by another match of one of the players",
+			len(candidates[order[s.deepest]]))
+		if s.steps >= maxSteps {
+			reason = "no schedule found within the search limit; " + reason
+		}
+		return nil, &ConflictError{Conflicts: []Conflict{{
+			MatchID: stuck.ID,
+			Player1: stuck.Player1,
+			Player2: stuck.Player2,
+			Reasons: append([]string{reason}, unavailabilityReasons(stuck, sorted, unavailability)...),
+		}}}
+	}
+
+	result := make(map[uint]time.Time, len(matches))
+	for i, match := range matches {
+		result[match.ID] = sorted[s.assigned[i]]
+	}
+	return result, nil
+}
+
+type search struct {
+	matches        []models.Match
+	candidates     [][]int
+	order          []int
+	matchesPerSlot int
+	assigned       []int
+	slotLoad       []int
+	busy           []map[string]bool
+	steps          int
+	deepest        int
+}
+
+func (s *search) place(depth int) bool {
+	if depth == len(s.order) {
+		return true
+	}
+	if depth > s.deepest {
+		s.deepest = depth
+	}
+
+	i := s.order[depth]
+	match := s.matches[i]
+	for _, slot := range s.candidates[i] {
+		s.steps++
+		if s.steps >= maxSteps {
+			return false
+		}
+		if s.slotLoad[slot] >= s.matchesPerSlot || s.busy[slot][match.Player1] || s.busy[slot][match.Player2] {
+			continue
+		}
+
+		s.assigned[i] = slot
+		s.slotLoad[slot]++
+		s.busy[slot][match.Player1] = true
+		s.busy[slot][match.Player2] = true
+
+		if s.place(depth + 1) {
+			return true
+		}
+
+		s.assigned[i] = -1
+		s.slotLoad[slot]--
+		delete(s.busy[slot], match.Player1)
+		delete(s.busy[slot], match.Player2)
+	}
+	return false
+}
+
+func blockingRules(match models.Match, slot time.Time, unavailability map[string][]models.PlayerUnavailability) []string {
+	var rules []string
+	for _, player := range []string{match.Player1, match.Player2} {
+		for _, rule := range unavailability[player] {
+			if rule.Blocks(slot) {
+				rules = append(rules, player+": "+rule.Describe())
+			}
+		}
+	}
+	return rules
+}
+
+// unavailabilityReasons lists the distinct constraints that rule out slots for a match
+func unavailabilityReasons(match models.Match, slots []time.Time, unavailability map[string][]models.PlayerUnavailability) []string {
+	seen := make(map[string]bool)
+	var reasons []string
+	for _, slot := range slots {
+		for _, rule := range blockingRules(match, slot, unavailability) {
+			if !seen[rule] {
+				seen[rule] = true
+				reasons = append(reasons, rule)
+			}
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,126 @@
+package seeding
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/projections"
+)
+
+type Method string
+
+const (
+	// Players in the order given by the organizer
+	MethodManual Method = "manual"
+	// Strongest player first, based on historic results
+	MethodRating Method = "rating"
+	// Final positions of a previous championship
+	MethodPreviousResult Method = "previous_result"
+	// Shuffled with a stored seed so the draw can be reproduced
+	MethodRandom Method = "random"
+)
+
+func (m Method) Valid() bool {
+	switch m {
+	case MethodManual, MethodRating, MethodPreviousResult, MethodRandom:
+		return true
+	}
+	return false
+}
+
+var ErrManualOrder = errors.New("player_ids must list every player of the championship exactly once")
+
+// byID returns the players in a stable base order that does not depend on
+// the order the database returned them in
+func byID(players []models.Player) []models.Player {
+	sorted := append([]models.Player(nil), players...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// Manual orders the players as listed in playerIDs, which must contain every
+// player exactly once
+func Manual(players []models.Player, playerIDs []uint) ([]models.Player, error) {
+	if len(playerIDs) != len(players) {
+		return nil, ErrManualOrder
+	}
+
+	byPlayerID := make(map[uint]models.Player, len(players))
+	for _, player := range players {
+		byPlayerID[player.ID] = player
+	}
+
+	seeded := make([]models.Player, 0, len(players))
+	for _, id := range playerIDs {
+		player, ok := byPlayerID[id]
+		if !ok {
+			return nil, ErrManualOrder
+		}
+		delete(byPlayerID, id)
+		seeded = append(seeded, player)
+	}
+
+	return seeded, nil
+}
+
+// Rating puts the players with the best historic results first. Players
+// without history are treated as average.
+func Rating(players []models.Player, strengths map[string]projections.Strength) []models.Player {
+	rating := func(player models.Player) float64 {
+		if strength, ok := strengths[player.Name]; ok {
+			return strength.WinRate
+		}
+		return 0.5
+	}
+
+	seeded := byID(players)
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return rating(seeded[i]) > rating(seeded[j])
+	})
+	return seeded
+}
+
+// PreviousResult orders players by their position in a previous championship.
+// ranking lists player names from first to last; players that did not take
+// part follow in registration order.
+func PreviousResult(players []models.Player, ranking []string) []models.Player {
+	positions := make(map[string]int, len(ranking))
+	for i, name := range ranking {
+		positions[name] = i
+	}
+	position := func(player models.Player) int {
+		if p, ok := positions[player.Name]; ok {
+			return p
+		}
+		return len(ranking)
+	}
+
+	seeded := byID(players)
+	sort.SliceStable(seeded, func(i, j int) bool {
+		return position(seeded[i]) < position(seeded[j])
+	})
+	return seeded
+}
+
+// Random shuffles the players deterministically, the same seed always
+// produces the same draw
+func Random(players []models.Player, seed int64) []models.Player {
+	seeded := byID(players)
+	rand.New(rand.NewSource(seed)).Shuffle(len(seeded), func(i, j int) {
+		seeded[i], seeded[j] = seeded[j], seeded[i]
+	})
+	return seeded
+}
+
+// MaxSeed is the largest seed. Seeds are sent as JSON numbers, which
+// JavaScript clients only read exactly up to 2^53 - 1.
+const MaxSeed = 1<<53 - 1
+
+// NewSeed returns a fresh seed for a random draw, between 0 and MaxSeed
+func NewSeed() int64 {
+	return rand.Int63n(MaxSeed + 1)
+}
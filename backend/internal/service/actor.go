@@ -0,0 +1,116 @@
+package service
+
+import (
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+)
+
+// Actor is whoever performs an operation. Handlers implement it from the
+// request's credentials. Actors do not query through the store, so services
+// check permissions before opening a transaction.
+type Actor interface {
+	// Role returns the actor's role in a championship, empty if it has none
+	Role(championshipID uint) (models.ChampionshipRole, error)
+	// OrganizationRole returns the actor's role in an organization, empty if
+	// it is no member
+	OrganizationRole(organizationID uint) (models.OrganizationRole, error)
+	// Player returns the player linked to the actor, or nil
+	Player() (*models.Player, error)
+}
+
+// System is an actor with full access, for jobs and command line tools
+var System Actor = systemActor{}
+
+type systemActor struct{}
+
+func (systemActor) Role(championshipID uint) (models.ChampionshipRole, error) {
+	return models.ChampionshipRoleOwner, nil
+}
+
+func (systemActor) OrganizationRole(organizationID uint) (models.OrganizationRole, error) {
+	return models.OrganizationRoleOwner, nil
+}
+
+func (systemActor) Player() (*models.Player, error) {
+	return nil, nil
+}
+
+// requireRole fails unless the actor has at least the given role in the championship
+func requireRole(actor Actor, championshipID uint, min models.ChampionshipRole) error {
+	role, err := actor.Role(championshipID)
+	if err != nil {
+		return failed("Failed to check permissions", err)
+	}
+	if !role.AtLeast(min) {
+		return forbidden(problem.CodeForbidden, "Insufficient permissions")
+	}
+	return nil
+}
+
+// requireOrganizationRole fails unless the actor has at least the given role
+// in the organization
+func requireOrganizationRole(actor Actor, organizationID uint, min models.OrganizationRole) error {
+	role, err := actor.OrganizationRole(organizationID)
+	if err != nil {
+		return failed("Failed to check permissions", err)
+	}
+	if !role.AtLeast(min) {
+		return forbidden(problem.CodeForbidden, "Insufficient permissions")
+	}
+	return nil
+}
+
+// isReferee reports whether the actor may report any match of the championship
+func isReferee(actor Actor, championshipID uint) (bool, error) {
+	role, err := actor.Role(championshipID)
+	if err != nil {
+		return false, failed("Failed to check permissions", err)
+	}
+	return role.AtLeast(models.ChampionshipRoleReferee), nil
+}
+
+// requireMatchReporter allows referees and above to report any match of the
+// championship, while players may only report their own matches
+func requireMatchReporter(actor Actor, match models.Match) error {
+	referee, err := isReferee(actor, match.ChampionshipID)
+	if err != nil || referee {
+		return err
+	}
+
+	player, err := actor.Player()
+	if err != nil {
+		return failed("Failed to check permissions", err)
+	}
+
+	if player == nil || player.OrganizationID != match.OrganizationID ||
+		(player.Name != match.Player1 && player.Name != match.Player2) {
+		return forbidden(problem.CodeNotOwnMatch, "Players can only report results for their own matches")
+	}
+
+	return nil
+}
+
+// requirePlayerManager allows the actor linked to the player, or an organizer
+// of every championship the player belongs to. Players outside championships
+// need an admin of the organization. Championships must be preloaded.
+func requirePlayerManager(actor Actor, player models.Player) error {
+	linked, err := actor.Player()
+	if err != nil {
+		return failed("Failed to check permissions", err)
+	}
+	if linked != nil && linked.ID == player.ID {
+		return nil
+	}
+
+	if len(player.Championships) == 0 {
+		return requireOrganizationRole(actor, player.OrganizationID, models.OrganizationRoleAdmin)
+	}
+
+	for _, championship := range player.Championships {
+		if err := requireRole(actor, championship.ID, models.ChampionshipRoleOrganizer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"scoretracker/backend/internal/auth"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+)
+
+type APIKeyService interface {
+	// List returns the API keys of a championship, newest first
+	List(organizationID uint, championshipID uint) ([]models.APIKey, error)
+	// Create issues a key for the championship. The plain key is returned
+	// once, only its hash is stored.
+	Create(organizationID uint, championshipID uint, key models.APIKey) (models.APIKey, string, error)
+	Revoke(organizationID uint, championshipID uint, id uint) (models.APIKey, error)
+}
+
+type apiKeyService struct {
+	store repository.Store
+}
+
+func NewAPIKeyService(store repository.Store) APIKeyService {
+	return &apiKeyService{store: store}
+}
+
+func (s *apiKeyService) List(organizationID uint, championshipID uint) ([]models.APIKey, error) {
+	if _, err := loadChampionship(s.store, organizationID, championshipID); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.store.APIKeys().List(championshipID)
+	if err != nil {
+		return nil, failed("Failed to fetch API keys", err)
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) Create(organizationID uint, championshipID uint, key models.APIKey) (models.APIKey, string, error) {
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(time.Now()) {
+		return key, "", invalidField("expires_at", problem.FieldOutOfRange, "Expiry must be in the future")
+	}
+	if _, err := loadChampionship(s.store, organizationID, championshipID); err != nil {
+		return key, "", err
+	}
+
+	plain, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return key, "", failed("Failed to create API key", err)
+	}
+
+	key.ChampionshipID = championshipID
+	key.Prefix = prefix
+	key.KeyHash = hash
+	if err := s.store.APIKeys().Create(&key); err != nil {
+		return key, "", failed("Failed to create API key", err)
+	}
+	return key, plain, nil
+}
+
+func (s *apiKeyService) Revoke(organizationID uint, championshipID uint, id uint) (models.APIKey, error) {
+	var key models.APIKey
+	err := s.store.Transaction(func(store repository.Store) error {
+		if _, err := loadChampionship(store, organizationID, championshipID); err != nil {
+			return err
+		}
+
+		var err error
+		key, err = store.APIKeys().Get(championshipID, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return notFound(problem.CodeAPIKeyNotFound, "API key not found")
+		}
+		if err != nil {
+			return failed("Failed to fetch API key", err)
+		}
+
+		if key.RevokedAt != nil {
+			return invalid(problem.CodeAPIKeyRevoked, "API key is already revoked")
+		}
+
+		if err := store.APIKeys().Revoke(&key, time.Now()); err != nil {
+			return failed("Failed to revoke API key", err)
+		}
+		return nil
+	})
+	return key, err
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/standings"
+)
+
+type ChampionshipService interface {
+	// List returns a page of the championships of the organization that pass the filter
+	List(organizationID uint, filter repository.ChampionshipFilter, options repository.ListOptions) ([]models.Championship, repository.Page, error)
+	// Get returns a championship with its players and matches
+	Get(organizationID uint, id uint) (models.Championship, error)
+	// Create stores a new championship owned by the given user
+	Create(organizationID uint, ownerID uint, championship models.Championship) (models.Championship, error)
+	// Update applies the changes of a request to the stored championship and
+	// saves it. Like all changes, it fails if version is set and the
+	// championship has another one.
+	Update(organizationID uint, id uint, version uint, apply func(*models.Championship)) (models.Championship, error)
+	Delete(organizationID uint, id uint) error
+	Finalize(organizationID uint, id uint, version uint) (models.Championship, error)
+	// Standings returns the table with the results of every player, only
+	// counting matches finished until asOf if given
+	Standings(organizationID uint, id uint, asOf *time.Time) ([]standings.Row, error)
+	// StandingsHistory returns a snapshot after every day on which matches were
+	// finished, or after every match if byMatch is set
+	StandingsHistory(organizationID uint, id uint, byMatch bool) ([]StandingsSnapshot, error)
+	// Draw returns the championship with the seeds of its stored draw
+	Draw(organizationID uint, id uint) (models.Championship, error)
+	// JoinSeason adds the championship to a season of the organization, or
+	// changes its weight if it already belongs to it
+	JoinSeason(organizationID uint, id uint, version uint, seasonID uint, weight float64) (models.Championship, error)
+	// LeaveSeason removes the championship from the season it belongs to
+	LeaveSeason(organizationID uint, id uint, version uint, seasonID uint) (models.Championship, error)
+	// Calendar returns the championship with its scheduled matches
+	Calendar(organizationID uint, id uint) (models.Championship, []models.Match, error)
+}
+
+type StandingsSnapshot struct {
+	Date      string               `json:"date,omitempty"`
+	MatchID   uint                 `json:"match_id,omitempty"`
+	AsOf      time.Time            `json:"as_of"`
+	Standings []standings.Standing `json:"standings"`
+	// The standings with the results of every player, sent by version 2
+	Rows []standings.Row `json:"-"`
+}
+
+type championshipService struct {
+	store repository.Store
+}
+
+func NewChampionshipService(store repository.Store) ChampionshipService {
+	return &championshipService{store: store}
+}
+
+// loadChampionship fetches a championship and maps a missing one to a not found error
+func loadChampionship(store repository.Store, organizationID uint, id uint, preloads ...string) (models.Championship, error) {
+	championship, err := store.Championships().Get(organizationID, id, preloads...)
+	if errors.Is(err, repository.ErrNotFound) {
+		return championship, notFound(problem.CodeChampionshipNotFound, "Championship not found")
+	}
+	if err != nil {
+		return championship, failed("Failed to fetch championship", err)
+	}
+	return championship, nil
+}
+
+func (s *championshipService) List(organizationID uint, filter repository.ChampionshipFilter, options repository.ListOptions) ([]models.Championship, repository.Page, error) {
+	if err := checkListOptions(options); err != nil {
+		return nil, repository.Page{}, err
+	}
+	switch filter.Status {
+	case "", models.ChampionshipStatusDraft, models.ChampionshipStatusFinalized:
+	default:
+		return nil, repository.Page{}, invalidField("status", problem.FieldInvalidValue, "Status must be draft or finalized")
+	}
+
+	championships, page, err := s.store.Championships().List(organizationID, filter, options)
+	if err != nil {
+		return nil, page, listFailed("Failed to fetch championships", err)
+	}
+	return championships, page, nil
+}
+
+func (s *championshipService) Get(organizationID uint, id uint) (models.Championship, error) {
+	return loadChampionship(s.store, organizationID, id, "Players", "Matches")
+}
+
+func (s *championshipService) Create(organizationID uint, ownerID uint, championship models.Championship) (models.Championship, error) {
+	if championship.Name == "" {
+		return championship, invalidField("name", problem.FieldRequired, "Name is required")
+	}
+
+	championship.OrganizationID = organizationID
+	// Championships join seasons through the season endpoints
+	championship.SeasonID = nil
+	championship.SeasonWeight = 1
+	// The draw is recorded when matches are generated
+	championship.SeedingMethod, championship.DrawSeed, championship.Seeds = "", nil, nil
+
+	// The creator becomes the owner of the championship
+	if err := s.store.Transaction(func(store repository.Store) error {
+		if err := store.Championships().Create(&championship); err != nil {
+			return err
+		}
+		return store.Championships().AddMember(&models.ChampionshipMember{
+			ChampionshipID: championship.ID,
+			UserID:         ownerID,
+			Role:           models.ChampionshipRoleOwner,
+		})
+	}); err != nil {
+		return championship, failed("Failed to create championship", err)
+	}
+
+	return championship, nil
+}
+
+func (s *championshipService) Update(organizationID uint, id uint, version uint, apply func(*models.Championship)) (models.Championship, error) {
+	return s.update(organizationID, id, version, func(store repository.Store, championship *models.Championship) error {
+		apply(championship)
+		return nil
+	})
+}
+
+// update saves the changes of apply to the locked championship, unless apply
+// rejects them. Apply reads through the store of the transaction.
+func (s *championshipService) update(organizationID uint, id uint, version uint, apply func(repository.Store, *models.Championship) error) (models.Championship, error) {
+	var championship models.Championship
+	err := s.store.Transaction(func(store repository.Store) error {
+		var err error
+		championship, err = loadChampionship(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, championship.Version, "Championship"); err != nil {
+			return err
+		}
+
+		if err := apply(store, &championship); err != nil {
+			return err
+		}
+		championship.Version++
+
+		if err := store.Championships().Save(&championship); err != nil {
+			return failed("Failed to update championship", err)
+		}
+		return nil
+	})
+	return championship, err
+}
+
+func (s *championshipService) Delete(organizationID uint, id uint) error {
+	championship, err := loadChampionship(s.store, organizationID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Championships().Delete(&championship); err != nil {
+		return failed("Failed to delete championship", err)
+	}
+	return nil
+}
+
+func (s *championshipService) Finalize(organizationID uint, id uint, version uint) (models.Championship, error) {
+	// The championship stays locked while its players are counted, so none
+	// can leave it before it is finalized
+	if err := s.store.Transaction(func(store repository.Store) error {
+		championship, err := loadChampionship(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(version, championship.Version, "Championship"); err != nil {
+			return err
+		}
+
+		if championship.Status == models.ChampionshipStatusFinalized {
+			return invalid(problem.CodeChampionshipFinalized, "Championship is already finalized")
+		}
+
+		playerCount, err := store.Championships().CountPlayers(id)
+		if err != nil {
+			return failed("Failed to check players", err)
+		}
+		if playerCount < 2 {
+			return invalid(problem.CodeNotEnoughPlayers, "At least 2 players are required to finalize a championship")
+		}
+
+		if err := store.Championships().UpdateStatus(id, models.ChampionshipStatusFinalized); err != nil {
+			return failed("Failed to finalize championship", err)
+		}
+		return nil
+	}); err != nil {
+		return models.Championship{}, err
+	}
+
+	// Reload the championship to return the updated version
+	championship, err := s.store.Championships().Get(organizationID, id)
+	if err != nil {
+		return championship, failed("Failed to reload championship", err)
+	}
+
+	return championship, nil
+}
+
+func (s *championshipService) Standings(organizationID uint, id uint, asOf *time.Time) ([]standings.Row, error) {
+	if _, err := loadChampionship(s.store, organizationID, id); err != nil {
+		return nil, err
+	}
+
+	matches, err := s.store.Matches().FinishedInChampionship(id, asOf)
+	if err != nil {
+		return nil, failed("Failed to fetch matches", err)
+	}
+
+	players, err := s.playerNames(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return standings.Rows(players, matches), nil
+}
+
+func (s *championshipService) StandingsHistory(organizationID uint, id uint, byMatch bool) ([]StandingsSnapshot, error) {
+	if _, err := loadChampionship(s.store, organizationID, id); err != nil {
+		return nil, err
+	}
+
+	finished, err := s.store.Matches().FinishedInChampionship(id, nil)
+	if err != nil {
+		return nil, failed("Failed to fetch matches", err)
+	}
+
+	matches := make([]models.Match, 0, len(finished))
+	for _, match := range finished {
+		if match.FinishedAt != nil {
+			matches = append(matches, match)
+		}
+	}
+
+	players, err := s.playerNames(id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]StandingsSnapshot, 0)
+	for i, match := range matches {
+		// Snapshot after the last match of each day, or after each match
+		date := match.FinishedAt.UTC().Format(models.DateLayout)
+		if !byMatch && i+1 < len(matches) && matches[i+1].FinishedAt.UTC().Format(models.DateLayout) == date {
+			continue
+		}
+
+		snapshot := StandingsSnapshot{AsOf: *match.FinishedAt, Rows: standings.Rows(players, matches[:i+1])}
+		snapshot.Standings = make([]standings.Standing, 0, len(snapshot.Rows))
+		for _, row := range snapshot.Rows {
+			snapshot.Standings = append(snapshot.Standings, row.Standing)
+		}
+		if byMatch {
+			snapshot.MatchID = match.ID
+		} else {
+			snapshot.Date = date
+		}
+		history = append(history, snapshot)
+	}
+
+	return history, nil
+}
+
+func (s *championshipService) Draw(organizationID uint, id uint) (models.Championship, error) {
+	championship, err := loadChampionship(s.store, organizationID, id, "Seeds", "Seeds.Player")
+	if err != nil {
+		return championship, err
+	}
+
+	if championship.SeedingMethod == "" {
+		return championship, notFound(problem.CodeDrawNotFound, "No draw has been made for this championship")
+	}
+
+	sort.Slice(championship.Seeds, func(i, j int) bool {
+		return championship.Seeds[i].Seed < championship.Seeds[j].Seed
+	})
+	return championship, nil
+}
+
+func (s *championshipService) JoinSeason(organizationID uint, id uint, version uint, seasonID uint, weight float64) (models.Championship, error) {
+	return s.update(organizationID, id, version, func(store repository.Store, championship *models.Championship) error {
+		if _, err := loadSeason(store, organizationID, seasonID); err != nil {
+			return err
+		}
+		if championship.SeasonID != nil && *championship.SeasonID != seasonID {
+			return conflict(problem.CodeChampionshipInOtherSeason, "Championship already belongs to another season")
+		}
+		championship.SeasonID = &seasonID
+		championship.SeasonWeight = weight
+		return nil
+	})
+}
+
+func (s *championshipService) LeaveSeason(organizationID uint, id uint, version uint, seasonID uint) (models.Championship, error) {
+	return s.update(organizationID, id, version, func(store repository.Store, championship *models.Championship) error {
+		if _, err := loadSeason(store, organizationID, seasonID); err != nil {
+			return err
+		}
+		if championship.SeasonID == nil || *championship.SeasonID != seasonID {
+			return notFound(problem.CodeChampionshipNotInSeason, "Championship is not part of this season")
+		}
+		championship.SeasonID = nil
+		championship.SeasonWeight = 1
+		return nil
+	})
+}
+
+func (s *championshipService) Calendar(organizationID uint, id uint) (models.Championship, []models.Match, error) {
+	championship, err := loadChampionship(s.store, organizationID, id)
+	if err != nil {
+		return championship, nil, err
+	}
+
+	matches, err := s.store.Matches().Scheduled(organizationID, repository.ScheduleFilter{ChampionshipID: &championship.ID})
+	if err != nil {
+		return championship, nil, failed("Failed to fetch matches", err)
+	}
+	return championship, matches, nil
+}
+
+// playerNames returns the names of all players in a championship
+func (s *championshipService) playerNames(id uint) ([]string, error) {
+	players, err := s.store.Championships().Players(id)
+	if err != nil {
+		return nil, failed("Failed to fetch players", err)
+	}
+
+	names := make([]string, 0, len(players))
+	for _, player := range players {
+		names = append(names, player.Name)
+	}
+	return names, nil
+}
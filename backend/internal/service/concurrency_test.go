@@ -0,0 +1,249 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"scoretracker/backend/internal/database"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB opens a migrated in-memory SQLite database of its own for the
+// test, or the Postgres database of TEST_POSTGRES_DSN if it is set. Tests
+// create a new organization for their data, so they can share Postgres.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	if os.Getenv("TEST_POSTGRES_DSN") != "" {
+		return openPostgres(t)
+	}
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := database.OpenSQLite("file:"+name+"?mode=memory&cache=shared", &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	return migrated(t, db, err)
+}
+
+// openPostgres opens the Postgres database of TEST_POSTGRES_DSN, or skips the
+// test. Tests of concurrent requests need it: SQLite runs every transaction
+// on one connection, one after the other, and ignores row locks, so the
+// tests would pass without them.
+func openPostgres(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set, concurrent requests are only tested on Postgres")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	return migrated(t, db, err)
+}
+
+func migrated(t *testing.T, db *gorm.DB, err error) *gorm.DB {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func create(t *testing.T, db *gorm.DB, value interface{}) {
+	t.Helper()
+	if err := db.Create(value).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hasCode(err error, code string) bool {
+	var serviceErr *Error
+	return errors.As(err, &serviceErr) && serviceErr.Code == code
+}
+
+// run calls work from n goroutines at once and waits for them
+func run(n int, work func(i int)) {
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			work(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestGenerateRoundRobinConcurrently(t *testing.T) {
+	db := openPostgres(t)
+
+	organization := models.Organization{Name: "Organization"}
+	create(t, db, &organization)
+	championship := models.Championship{OrganizationID: organization.ID, Name: "Championship", Status: models.ChampionshipStatusFinalized}
+	create(t, db, &championship)
+	for i := 1; i <= 4; i++ {
+		create(t, db, &models.Player{
+			OrganizationID: organization.ID,
+			Name:           fmt.Sprintf("Player %d", i),
+			Championships:  []models.Championship{championship},
+		})
+	}
+
+	matches := NewMatchService(repository.NewStore(db), nil)
+	var generated atomic.Int32
+	errs := make([]error, 8)
+	run(len(errs), func(i int) {
+		if _, err := matches.GenerateRoundRobin(organization.ID, championship.ID, 0, DrawRequest{}); err != nil {
+			errs[i] = err
+			return
+		}
+		generated.Add(1)
+	})
+
+	if n := generated.Load(); n != 1 {
+		t.Errorf("draw generated %d times, want once", n)
+	}
+	for _, err := range errs {
+		if err != nil && !hasCode(err, problem.CodeMatchesExist) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	var created []models.Match
+	if err := db.Where("championship_id = ?", championship.ID).Find(&created).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 6 {
+		t.Errorf("got %d matches, want 6", len(created))
+	}
+	pairs := make(map[string]bool)
+	for _, match := range created {
+		players := []string{match.Player1, match.Player2}
+		if players[0] > players[1] {
+			players[0], players[1] = players[1], players[0]
+		}
+		pair := players[0] + " - " + players[1]
+		if pairs[pair] {
+			t.Errorf("duplicate match %s", pair)
+		}
+		pairs[pair] = true
+	}
+}
+
+// playerFixture is a player in the first of three open championships
+type playerFixture struct {
+	organization  models.Organization
+	championships []models.Championship
+	player        models.Player
+}
+
+func newPlayerFixture(t *testing.T, db *gorm.DB) playerFixture {
+	t.Helper()
+	var f playerFixture
+	f.organization = models.Organization{Name: "Organization"}
+	create(t, db, &f.organization)
+	f.championships = make([]models.Championship, 3)
+	for i := range f.championships {
+		f.championships[i] = models.Championship{OrganizationID: f.organization.ID, Name: fmt.Sprintf("Championship %d", i+1)}
+		create(t, db, &f.championships[i])
+	}
+	f.player = models.Player{OrganizationID: f.organization.ID, Name: "Player", Championships: f.championships[:1]}
+	create(t, db, &f.player)
+	return f
+}
+
+// championshipCount counts the championships the player is in
+func (f playerFixture) championshipCount(t *testing.T, db *gorm.DB) int64 {
+	var n int64
+	if err := db.Table("player_championships").Where("player_id = ?", f.player.ID).Count(&n).Error; err != nil {
+		t.Error(err)
+	}
+	return n
+}
+
+func TestUpdatePlayerConcurrently(t *testing.T) {
+	db := openPostgres(t)
+	f := newPlayerFixture(t, db)
+	players := NewPlayerService(repository.NewStore(db))
+	selections := [][]uint{
+		{f.championships[0].ID},
+		{f.championships[1].ID},
+		{f.championships[0].ID, f.championships[2].ID},
+	}
+
+	var done atomic.Bool
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for !done.Load() {
+				if n := f.championshipCount(t, db); n == 0 {
+					t.Error("player has no championships")
+					return
+				}
+			}
+		}()
+	}
+
+	run(8, func(i int) {
+		for j := 0; j < 20; j++ {
+			_, err := players.Update(f.organization.ID, System, f.player.ID, 0, nil, selections[(i+j)%len(selections)])
+			// Another update can change the championships between the
+			// checks and the transaction, the caller then retries
+			if err != nil && !hasCode(err, problem.CodeConcurrentUpdate) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}
+	})
+	done.Store(true)
+	readers.Wait()
+
+	if n := f.championshipCount(t, db); n == 0 {
+		t.Error("player has no championships")
+	}
+}
+
+func TestFailedPlayerUpdateKeepsChampionships(t *testing.T) {
+	db := openTestDB(t)
+	f := newPlayerFixture(t, db)
+	players := NewPlayerService(repository.NewStore(db))
+
+	// Fail adding the player to its championships after the old ones were
+	// removed
+	const callback = "test:fail_player_championships"
+	if err := db.Callback().Create().Before("gorm:create").Register(callback, func(tx *gorm.DB) {
+		if tx.Statement.Table == "player_championships" {
+			tx.AddError(errors.New("injected failure"))
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Callback().Create().Remove(callback)
+
+	if _, err := players.Update(f.organization.ID, System, f.player.ID, 0, nil, []uint{f.championships[1].ID, f.championships[2].ID}); err == nil {
+		t.Fatal("update succeeded despite the failure")
+	}
+	if n := f.championshipCount(t, db); n != 1 {
+		t.Errorf("player has %d championships after the failed update, want 1", n)
+	}
+}
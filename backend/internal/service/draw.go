@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/projections"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/seeding"
+	"scoretracker/backend/internal/standings"
+)
+
+// DrawRequest selects how players are seeded before matches are generated.
+// Without a method a random draw is made.
+type DrawRequest struct {
+	Method                 seeding.Method `json:"method"`
+	PlayerIDs              []uint         `json:"player_ids"`
+	PreviousChampionshipID uint           `json:"previous_championship_id"`
+	// Repeats a random draw, between 0 and seeding.MaxSeed
+	Seed *int64 `json:"seed"`
+}
+
+type Draw struct {
+	Method  seeding.Method  `json:"method"`
+	Seed    *int64          `json:"seed,omitempty"`
+	Players []models.Player `json:"players"`
+	Matches []models.Match  `json:"matches"`
+}
+
+// drawChampionship loads a championship that is ready for its matches to be
+// generated. Within a transaction the championship stays locked, so no other
+// draw can create matches for it until the transaction ends.
+func drawChampionship(store repository.Store, organizationID uint, championshipID uint) (models.Championship, error) {
+	championship, err := loadChampionship(store, organizationID, championshipID, "Players")
+	if err != nil {
+		return championship, err
+	}
+
+	if championship.Status != models.ChampionshipStatusFinalized {
+		return championship, invalid(problem.CodeChampionshipNotFinalized, "Championship must be finalized before generating matches")
+	}
+
+	if len(championship.Players) < 2 {
+		return championship, invalid(problem.CodeNotEnoughPlayers, "At least 2 players are required to generate matches")
+	}
+
+	// Check if matches already exist for this championship
+	existing, err := store.Matches().CountInChampionship(championship.ID)
+	if err != nil {
+		return championship, failed("Failed to check existing matches", err)
+	}
+	if existing > 0 {
+		return championship, invalid(problem.CodeMatchesExist, "Matches already exist for this championship")
+	}
+
+	return championship, nil
+}
+
+// makeDraw seeds the championship's players and pairs them round-robin
+func makeDraw(store repository.Store, championship models.Championship, request DrawRequest) (Draw, error) {
+	if request.Method == "" {
+		request.Method = seeding.MethodRandom
+	}
+	if !request.Method.Valid() {
+		return Draw{}, invalidField("method", problem.FieldInvalidValue, "Method must be one of manual, rating, previous_result or random")
+	}
+
+	result := Draw{Method: request.Method}
+
+	switch request.Method {
+	case seeding.MethodManual:
+		players, err := seeding.Manual(championship.Players, request.PlayerIDs)
+		if err != nil {
+			return result, invalidField("player_ids", problem.FieldInvalidValue, err.Error())
+		}
+		result.Players = players
+
+	case seeding.MethodRating:
+		history, err := store.Matches().Finished(championship.OrganizationID)
+		if err != nil {
+			return result, failed("Failed to fetch match history", err)
+		}
+		result.Players = seeding.Rating(championship.Players, projections.Strengths(history))
+
+	case seeding.MethodPreviousResult:
+		previous, err := store.Championships().Get(championship.OrganizationID, request.PreviousChampionshipID, "Players")
+		if errors.Is(err, repository.ErrNotFound) {
+			return result, invalidField("previous_championship_id", problem.FieldNotFound, "Previous championship not found")
+		}
+		if err != nil {
+			return result, failed("Failed to fetch previous championship", err)
+		}
+
+		matches, err := store.Matches().FinishedInChampionship(previous.ID, nil)
+		if err != nil {
+			return result, failed("Failed to fetch matches", err)
+		}
+
+		names := make([]string, 0, len(previous.Players))
+		for _, player := range previous.Players {
+			names = append(names, player.Name)
+		}
+		ranking := standings.Order(names, standings.Points(matches), matches)
+		result.Players = seeding.PreviousResult(championship.Players, ranking)
+
+	case seeding.MethodRandom:
+		seed := seeding.NewSeed()
+		if request.Seed != nil {
+			if *request.Seed < 0 || *request.Seed > seeding.MaxSeed {
+				return result, invalidField("seed", problem.FieldOutOfRange, fmt.Sprintf("Seed must be between 0 and %d", seeding.MaxSeed))
+			}
+			seed = *request.Seed
+		}
+		result.Seed = &seed
+		result.Players = seeding.Random(championship.Players, seed)
+	}
+
+	// Generate round-robin matches (each player plays against every other player once)
+	result.Matches = make([]models.Match, 0)
+	for i := 0; i < len(result.Players); i++ {
+		for j := i + 1; j < len(result.Players); j++ {
+			result.Matches = append(result.Matches, models.Match{
+				OrganizationID: championship.OrganizationID,
+				ChampionshipID: championship.ID,
+				Player1:        result.Players[i].Name,
+				Player2:        result.Players[j].Name,
+				Game:           championship.Name, // Use championship name as game name
+				Status:         models.MatchStatusPending,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+func (s *matchService) PreviewDraw(organizationID uint, championshipID uint, request DrawRequest) (Draw, error) {
+	championship, err := drawChampionship(s.store, organizationID, championshipID)
+	if err != nil {
+		return Draw{}, err
+	}
+	return makeDraw(s.store, championship, request)
+}
+
+func (s *matchService) GenerateRoundRobin(organizationID uint, championshipID uint, version uint, request DrawRequest) (Draw, error) {
+	var result Draw
+	err := s.store.Transaction(func(store repository.Store) error {
+		championship, err := drawChampionship(store, organizationID, championshipID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, championship.Version, "Championship"); err != nil {
+			return err
+		}
+
+		result, err = makeDraw(store, championship, request)
+		if err != nil {
+			return err
+		}
+
+		playerIDs := make([]uint, 0, len(result.Players))
+		for _, player := range result.Players {
+			playerIDs = append(playerIDs, player.ID)
+		}
+
+		// Create all matches together with the draw
+		if err := store.Matches().CreateAll(result.Matches); err != nil {
+			return failed("Failed to create matches", err)
+		}
+		if err := store.Championships().SaveDraw(championship.ID, string(result.Method), result.Seed, playerIDs); err != nil {
+			return failed("Failed to create matches", err)
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+
+	"scoretracker/backend/internal/problem"
+)
+
+type ErrorKind int
+
+const (
+	// The request breaks a business rule or references invalid data
+	KindInvalid ErrorKind = iota + 1
+	KindNotFound
+	KindForbidden
+	// The record changed since the version the caller expected
+	KindStale
+	// The request conflicts with the state of another record
+	KindConflict
+	// Storage or another dependency failed
+	KindInternal
+)
+
+// Error carries a message that can be shown to the caller, the kind of
+// failure, which handlers map to a status code, and a stable code from the
+// problem package
+type Error struct {
+	Kind ErrorKind
+	Code string
+	// Set if a single field of the request is invalid, Code is then a field code
+	Field   string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func invalid(code string, message string) error {
+	return &Error{Kind: KindInvalid, Code: code, Message: message}
+}
+
+func invalidField(field string, code string, message string) error {
+	return &Error{Kind: KindInvalid, Code: code, Field: field, Message: message}
+}
+
+func notFound(code string, message string) error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func forbidden(code string, message string) error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func conflict(code string, message string) error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func failed(message string, err error) error {
+	return &Error{Kind: KindInternal, Code: problem.CodeInternal, Message: message, Err: err}
+}
+
+// checkVersion fails unless the record has the version the caller expected.
+// Callers that expect no particular version pass 0.
+func checkVersion(expected uint, actual uint, record string) error {
+	if expected != 0 && expected != actual {
+		return &Error{Kind: KindStale, Code: problem.CodeVersionMismatch, Message: record + " was changed by another request"}
+	}
+	return nil
+}
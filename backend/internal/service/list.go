@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+)
+
+// MaxPageSize is the largest number of rows a page of a list may have
+const MaxPageSize = 100
+
+// DefaultPageSize is the number of rows of a page if the request sets no limit
+const DefaultPageSize = 25
+
+func checkListOptions(options repository.ListOptions) error {
+	if options.Limit < 0 || options.Limit > MaxPageSize {
+		return invalidField("limit", problem.FieldOutOfRange, fmt.Sprintf("Limit must be between 1 and %d", MaxPageSize))
+	}
+	return nil
+}
+
+// listFailed maps the errors of a repository list to service errors
+func listFailed(message string, err error) error {
+	var sortErr *repository.SortError
+	if errors.As(err, &sortErr) {
+		return invalidField("sort", problem.FieldInvalidValue, sortErr.Error())
+	}
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return invalidField("cursor", problem.FieldInvalidValue, "Invalid cursor")
+	}
+	return failed(message, err)
+}
+
+func validMatchStatus(status models.MatchStatus) bool {
+	switch status {
+	case models.MatchStatusPending, models.MatchStatusStarted, models.MatchStatusFinished,
+		models.MatchStatusPendingConfirmation, models.MatchStatusDisputed:
+		return true
+	}
+	return false
+}
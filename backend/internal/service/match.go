@@ -0,0 +1,474 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/scheduler"
+)
+
+type MatchService interface {
+	// List returns a page of the matches of the organization that pass the filter
+	List(organizationID uint, filter repository.MatchFilter, options repository.ListOptions) ([]models.Match, repository.Page, error)
+	Get(organizationID uint, id uint) (models.Match, error)
+	Create(organizationID uint, actor Actor, match models.Match) (models.Match, error)
+	// PlayerNames resolves the players of a new match, given by ID as in
+	// version 2 of the API, to the names matches are stored with
+	PlayerNames(organizationID uint, player1ID uint, player2ID uint) (string, string, error)
+	Delete(organizationID uint, id uint) error
+
+	// The changes of a match's state below fail if version is set and the
+	// match has another one
+	Start(organizationID uint, actor Actor, id uint, version uint) (models.Match, error)
+	UpdateScore(organizationID uint, actor Actor, id uint, version uint, player1Score int, player2Score int) (models.Match, error)
+	// Finish ends a match. Results reported by a player of a championship that
+	// requires confirmation wait for the opponent instead.
+	Finish(organizationID uint, actor Actor, id uint, version uint) (models.Match, error)
+	Confirm(organizationID uint, actor Actor, id uint, version uint) (models.Match, error)
+	Dispute(organizationID uint, actor Actor, id uint, version uint, reason string) (models.Match, error)
+	// Resolve sets the official score of a disputed match
+	Resolve(organizationID uint, id uint, version uint, player1Score int, player2Score int) (models.Match, error)
+	Disputes(organizationID uint, championshipID uint) ([]models.Match, error)
+
+	// PreviewDraw returns the draw GenerateRoundRobin would make without saving it
+	PreviewDraw(organizationID uint, championshipID uint, request DrawRequest) (Draw, error)
+	// GenerateRoundRobin seeds the players and creates a match for every pair.
+	// It fails if version is set and the championship has another one.
+	GenerateRoundRobin(organizationID uint, championshipID uint, version uint, request DrawRequest) (Draw, error)
+	// Schedule assigns the pending matches of a championship to time slots.
+	// Returns a *scheduler.ConflictError if no valid schedule exists.
+	Schedule(organizationID uint, championshipID uint, slots []time.Time, matchesPerSlot int) ([]models.Match, error)
+}
+
+type matchService struct {
+	store repository.Store
+	// Called after a result became official, to update derived data
+	resultsChanged func(match models.Match)
+}
+
+// NewMatchService returns a MatchService. resultsChanged is called with the
+// finished match whenever a result becomes official and may be nil.
+func NewMatchService(store repository.Store, resultsChanged func(match models.Match)) MatchService {
+	if resultsChanged == nil {
+		resultsChanged = func(models.Match) {}
+	}
+	return &matchService{store: store, resultsChanged: resultsChanged}
+}
+
+func (s *matchService) load(organizationID uint, id uint, preloads ...string) (models.Match, error) {
+	return loadMatch(s.store, organizationID, id, preloads...)
+}
+
+func loadMatch(store repository.Store, organizationID uint, id uint, preloads ...string) (models.Match, error) {
+	match, err := store.Matches().Get(organizationID, id, preloads...)
+	if errors.Is(err, repository.ErrNotFound) {
+		return match, notFound(problem.CodeMatchNotFound, "Match not found")
+	}
+	if err != nil {
+		return match, failed("Failed to fetch match", err)
+	}
+	return match, nil
+}
+
+// change applies fn to the match and saves it as its next version in one
+// transaction. The match stays locked meanwhile, so concurrent changes see
+// each other's result. authorize, if set, checks the actor's permissions on
+// the match beforehand.
+func (s *matchService) change(organizationID uint, id uint, version uint, authorize func(match models.Match) error, preloads []string, message string, fn func(match *models.Match) error) (models.Match, error) {
+	if authorize != nil {
+		match, err := s.load(organizationID, id)
+		if err != nil {
+			return match, err
+		}
+		if err := authorize(match); err != nil {
+			return match, err
+		}
+	}
+
+	var match models.Match
+	err := s.store.Transaction(func(store repository.Store) error {
+		var err error
+		match, err = loadMatch(store, organizationID, id, preloads...)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, match.Version, "Match"); err != nil {
+			return err
+		}
+		if err := fn(&match); err != nil {
+			return err
+		}
+		match.Version++
+		if err := store.Matches().Save(&match); err != nil {
+			return failed(message, err)
+		}
+		return nil
+	})
+	return match, err
+}
+
+func (s *matchService) List(organizationID uint, filter repository.MatchFilter, options repository.ListOptions) ([]models.Match, repository.Page, error) {
+	if err := checkListOptions(options); err != nil {
+		return nil, repository.Page{}, err
+	}
+	if filter.Status != "" && !validMatchStatus(filter.Status) {
+		return nil, repository.Page{}, invalidField("status", problem.FieldInvalidValue, "Status must be one of pending, started, finished, pending_confirmation or disputed")
+	}
+
+	matches, page, err := s.store.Matches().List(organizationID, filter, options)
+	if err != nil {
+		return nil, page, listFailed("Failed to fetch matches", err)
+	}
+	return matches, page, nil
+}
+
+func (s *matchService) Get(organizationID uint, id uint) (models.Match, error) {
+	return s.load(organizationID, id)
+}
+
+// requireMember checks that the named player is in the championship
+func requireMember(store repository.Store, organizationID uint, championshipID uint, name string, label string) error {
+	player, err := store.Players().FindByName(organizationID, name)
+	if errors.Is(err, repository.ErrNotFound) {
+		return invalid(problem.CodePlayerNotFound, label+" not found")
+	}
+	if err != nil {
+		return failed("Failed to verify players", err)
+	}
+
+	member, err := store.Players().InChampionship(player.ID, championshipID)
+	if err != nil {
+		return failed("Failed to verify players", err)
+	}
+	if !member {
+		return invalid(problem.CodePlayerNotInChampionship, label+" is not in this championship")
+	}
+	return nil
+}
+
+func (s *matchService) PlayerNames(organizationID uint, player1ID uint, player2ID uint) (string, string, error) {
+	var names [2]string
+	for i, id := range []uint{player1ID, player2ID} {
+		player, err := s.store.Players().Get(organizationID, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return "", "", invalidField(fmt.Sprintf("player%d_id", i+1), problem.FieldNotFound, "Player not found")
+		}
+		if err != nil {
+			return "", "", failed("Failed to fetch player", err)
+		}
+		names[i] = player.Name
+	}
+	return names[0], names[1], nil
+}
+
+func (s *matchService) Create(organizationID uint, actor Actor, match models.Match) (models.Match, error) {
+	if match.ChampionshipID == 0 {
+		return match, invalidField("championship_id", problem.FieldRequired, "Championship ID is required")
+	}
+
+	// Verify championship exists
+	championship, err := s.store.Championships().Get(organizationID, match.ChampionshipID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return match, invalid(problem.CodeChampionshipNotFound, "Championship not found")
+	}
+	if err != nil {
+		return match, failed("Failed to verify championship", err)
+	}
+
+	if err := requireRole(actor, championship.ID, models.ChampionshipRoleOrganizer); err != nil {
+		return match, err
+	}
+
+	// Validate that players are different
+	if match.Player1 == match.Player2 {
+		return match, invalid(problem.CodeSamePlayers, "Players must be different")
+	}
+
+	// Set default status if not provided
+	if match.Status == "" {
+		match.Status = models.MatchStatusPending
+	}
+
+	// The championship stays locked, so players cannot leave it before the
+	// match is created
+	err = s.store.Transaction(func(store repository.Store) error {
+		championship, err := store.Championships().Get(organizationID, match.ChampionshipID)
+		if err != nil {
+			return failed("Failed to verify championship", err)
+		}
+
+		// Verify players exist and are in the championship
+		if err := requireMember(store, organizationID, championship.ID, match.Player1, "Player1"); err != nil {
+			return err
+		}
+		if err := requireMember(store, organizationID, championship.ID, match.Player2, "Player2"); err != nil {
+			return err
+		}
+
+		match.OrganizationID = championship.OrganizationID
+		if err := store.Matches().Create(&match); err != nil {
+			return failed("Failed to create match", err)
+		}
+		return nil
+	})
+	return match, err
+}
+
+func (s *matchService) Delete(organizationID uint, id uint) error {
+	match, err := s.load(organizationID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Matches().Delete(&match); err != nil {
+		return failed("Failed to delete match", err)
+	}
+	return nil
+}
+
+func (s *matchService) Start(organizationID uint, actor Actor, id uint, version uint) (models.Match, error) {
+	return s.change(organizationID, id, version, reporter(actor), nil, "Failed to start match", func(match *models.Match) error {
+		if match.Status != models.MatchStatusPending {
+			return invalid(problem.CodeMatchNotPending, "Match is not in pending status")
+		}
+
+		now := time.Now()
+		match.Status = models.MatchStatusStarted
+		match.StartedAt = &now
+		return nil
+	})
+}
+
+func (s *matchService) UpdateScore(organizationID uint, actor Actor, id uint, version uint, player1Score int, player2Score int) (models.Match, error) {
+	return s.change(organizationID, id, version, reporter(actor), nil, "Failed to update match score", func(match *models.Match) error {
+		if match.Status != models.MatchStatusStarted {
+			return invalid(problem.CodeMatchNotStarted, "Match must be started to update score")
+		}
+
+		match.Player1Score = player1Score
+		match.Player2Score = player2Score
+		return nil
+	})
+}
+
+func (s *matchService) Finish(organizationID uint, actor Actor, id uint, version uint) (models.Match, error) {
+	var referee bool
+	var reportedBy *models.Player
+	authorize := func(match models.Match) error {
+		if err := requireMatchReporter(actor, match); err != nil {
+			return err
+		}
+
+		var err error
+		referee, err = isReferee(actor, match.ChampionshipID)
+		if err != nil {
+			return err
+		}
+		if !referee {
+			reportedBy, err = actor.Player()
+			if err != nil {
+				return failed("Failed to determine reporting player", err)
+			}
+		}
+		return nil
+	}
+
+	match, err := s.change(organizationID, id, version, authorize, []string{"Championship"}, "Failed to finish match", func(match *models.Match) error {
+		if match.Status != models.MatchStatusStarted {
+			return invalid(problem.CodeMatchNotStarted, "Match must be started to finish it")
+		}
+
+		now := time.Now()
+
+		// Results reported by a player only become official once the opponent confirms them
+		if match.Championship.RequireConfirmation && !referee {
+			if reportedBy == nil {
+				return failed("Failed to determine reporting player", nil)
+			}
+
+			match.Status = models.MatchStatusPendingConfirmation
+			match.ReportedBy = &reportedBy.Name
+			match.ReportedAt = &now
+		} else {
+			match.Finish(now)
+		}
+		return nil
+	})
+	if err != nil {
+		return match, err
+	}
+
+	if match.Status == models.MatchStatusFinished {
+		s.resultsChanged(match)
+	}
+
+	return match, nil
+}
+
+// reporter returns a check that the actor may report the match's result
+func reporter(actor Actor) func(match models.Match) error {
+	return func(match models.Match) error {
+		return requireMatchReporter(actor, match)
+	}
+}
+
+// requireOpponent checks that the match awaits confirmation and that the
+// player is the opponent of the player who reported the result
+func requireOpponent(player *models.Player, match models.Match) error {
+	if match.Status != models.MatchStatusPendingConfirmation || match.ReportedBy == nil {
+		return invalid(problem.CodeNoReportedResult, "Match has no result awaiting confirmation")
+	}
+
+	opponent := match.Player1
+	if *match.ReportedBy == match.Player1 {
+		opponent = match.Player2
+	}
+
+	if player == nil || player.OrganizationID != match.OrganizationID || player.Name != opponent {
+		return forbidden(problem.CodeNotOpponent, "Only the opponent can respond to a reported result")
+	}
+
+	return nil
+}
+
+func (s *matchService) Confirm(organizationID uint, actor Actor, id uint, version uint) (models.Match, error) {
+	player, err := actor.Player()
+	if err != nil {
+		return models.Match{}, failed("Failed to check permissions", err)
+	}
+
+	match, err := s.change(organizationID, id, version, nil, nil, "Failed to confirm result", func(match *models.Match) error {
+		if err := requireOpponent(player, *match); err != nil {
+			return err
+		}
+
+		match.Finish(time.Now())
+		return nil
+	})
+	if err != nil {
+		return match, err
+	}
+
+	s.resultsChanged(match)
+	return match, nil
+}
+
+func (s *matchService) Dispute(organizationID uint, actor Actor, id uint, version uint, reason string) (models.Match, error) {
+	player, err := actor.Player()
+	if err != nil {
+		return models.Match{}, failed("Failed to check permissions", err)
+	}
+
+	return s.change(organizationID, id, version, nil, nil, "Failed to dispute result", func(match *models.Match) error {
+		if err := requireOpponent(player, *match); err != nil {
+			return err
+		}
+
+		match.Status = models.MatchStatusDisputed
+		match.DisputeReason = strings.TrimSpace(reason)
+		return nil
+	})
+}
+
+func (s *matchService) Resolve(organizationID uint, id uint, version uint, player1Score int, player2Score int) (models.Match, error) {
+	match, err := s.change(organizationID, id, version, nil, nil, "Failed to resolve dispute", func(match *models.Match) error {
+		if match.Status != models.MatchStatusDisputed {
+			return invalid(problem.CodeMatchNotDisputed, "Match is not disputed")
+		}
+
+		match.Player1Score = player1Score
+		match.Player2Score = player2Score
+		match.Finish(time.Now())
+		return nil
+	})
+	if err != nil {
+		return match, err
+	}
+
+	s.resultsChanged(match)
+	return match, nil
+}
+
+func (s *matchService) Disputes(organizationID uint, championshipID uint) ([]models.Match, error) {
+	if _, err := loadChampionship(s.store, organizationID, championshipID); err != nil {
+		return nil, err
+	}
+
+	matches, err := s.store.Matches().WithStatus(championshipID, models.MatchStatusDisputed)
+	if err != nil {
+		return nil, failed("Failed to fetch disputes", err)
+	}
+
+	// Oldest report first
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i].ReportedAt, matches[j].ReportedAt
+		return a != nil && (b == nil || a.Before(*b))
+	})
+	return matches, nil
+}
+
+func (s *matchService) Schedule(organizationID uint, championshipID uint, slots []time.Time, matchesPerSlot int) ([]models.Match, error) {
+	var matches []models.Match
+	err := s.store.Transaction(func(store repository.Store) error {
+		championship, err := loadChampionship(store, organizationID, championshipID, "Players")
+		if err != nil {
+			return err
+		}
+
+		// Only matches that have not been played yet can be (re)scheduled
+		matches, err = store.Matches().WithStatus(championshipID, models.MatchStatusPending)
+		if err != nil {
+			return failed("Failed to fetch matches", err)
+		}
+		if len(matches) == 0 {
+			return invalid(problem.CodeNoPendingMatches, "No pending matches to schedule")
+		}
+
+		// Collect unavailability of all championship players, keyed by player name
+		playerNames := make(map[uint]string, len(championship.Players))
+		playerIDs := make([]uint, 0, len(championship.Players))
+		for _, player := range championship.Players {
+			playerNames[player.ID] = player.Name
+			playerIDs = append(playerIDs, player.ID)
+		}
+
+		rules, err := store.Players().Unavailability(playerIDs)
+		if err != nil {
+			return failed("Failed to fetch player unavailability", err)
+		}
+
+		unavailability := make(map[string][]models.PlayerUnavailability)
+		for _, rule := range rules {
+			name := playerNames[rule.PlayerID]
+			unavailability[name] = append(unavailability[name], rule)
+		}
+
+		assignments, err := scheduler.Schedule(matches, slots, matchesPerSlot, unavailability)
+		if err != nil {
+			var conflictErr *scheduler.ConflictError
+			if errors.As(err, &conflictErr) {
+				return conflictErr
+			}
+			return failed("Failed to schedule matches", err)
+		}
+
+		for i := range matches {
+			scheduledAt := assignments[matches[i].ID]
+			matches[i].ScheduledAt = &scheduledAt
+			if err := store.Matches().SetScheduledAt(matches[i].ID, scheduledAt); err != nil {
+				return failed("Failed to save schedule", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
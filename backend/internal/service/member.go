@@ -0,0 +1,142 @@
+package service
+
+import (
+	"errors"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+)
+
+type ChampionshipMemberService interface {
+	// List returns the members of a championship with their users
+	List(organizationID uint, championshipID uint) ([]models.ChampionshipMember, error)
+	// Set grants a role to a member of the organization, replacing any role
+	// the user already had in the championship. Only owners grant, change or
+	// take away the owner and organizer roles.
+	Set(organizationID uint, actor Actor, championshipID uint, userID uint, role models.ChampionshipRole) (models.ChampionshipMember, error)
+	Remove(organizationID uint, actor Actor, championshipID uint, userID uint) error
+}
+
+type championshipMemberService struct {
+	store repository.Store
+}
+
+func NewChampionshipMemberService(store repository.Store) ChampionshipMemberService {
+	return &championshipMemberService{store: store}
+}
+
+func (s *championshipMemberService) List(organizationID uint, championshipID uint) ([]models.ChampionshipMember, error) {
+	if _, err := loadChampionship(s.store, organizationID, championshipID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.ChampionshipMembers().List(championshipID)
+	if err != nil {
+		return nil, failed("Failed to fetch members", err)
+	}
+	return members, nil
+}
+
+func (s *championshipMemberService) Set(organizationID uint, actor Actor, championshipID uint, userID uint, role models.ChampionshipRole) (models.ChampionshipMember, error) {
+	var member models.ChampionshipMember
+	callerRole, err := actor.Role(championshipID)
+	if err != nil {
+		return member, failed("Failed to check permissions", err)
+	}
+	if role.AtLeast(models.ChampionshipRoleOrganizer) && callerRole != models.ChampionshipRoleOwner {
+		return member, forbidden(problem.CodeOwnerRequired, "Only owners can grant the owner or organizer role")
+	}
+
+	err = s.store.Transaction(func(store repository.Store) error {
+		if _, err := loadChampionship(store, organizationID, championshipID); err != nil {
+			return err
+		}
+
+		user, err := store.Users().Get(userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return invalid(problem.CodeUserNotFound, "User not found")
+		}
+		if err != nil {
+			return failed("Failed to fetch user", err)
+		}
+
+		if _, err := store.Organizations().GetMember(organizationID, user.ID); errors.Is(err, repository.ErrNotFound) {
+			return invalid(problem.CodeUserNotInOrganization, "User is not a member of this organization")
+		} else if err != nil {
+			return failed("Failed to check organization membership", err)
+		}
+
+		member, err = store.ChampionshipMembers().Get(championshipID, user.ID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			member = models.ChampionshipMember{ChampionshipID: championshipID, UserID: user.ID}
+		case err != nil:
+			return failed("Failed to fetch member", err)
+		case member.Role.AtLeast(models.ChampionshipRoleOrganizer) && callerRole != models.ChampionshipRoleOwner:
+			return forbidden(problem.CodeOwnerRequired, "Only owners can change the role of owners and organizers")
+		case member.Role == models.ChampionshipRoleOwner && role != models.ChampionshipRoleOwner:
+			if err := requireOtherChampionshipOwner(store, championshipID, user.ID); err != nil {
+				return err
+			}
+		}
+
+		member.Role = role
+		if err := store.ChampionshipMembers().Save(&member); err != nil {
+			return failed("Failed to save member", err)
+		}
+		member.User = &user
+		return nil
+	})
+	return member, err
+}
+
+func (s *championshipMemberService) Remove(organizationID uint, actor Actor, championshipID uint, userID uint) error {
+	callerRole, err := actor.Role(championshipID)
+	if err != nil {
+		return failed("Failed to check permissions", err)
+	}
+
+	return s.store.Transaction(func(store repository.Store) error {
+		if _, err := loadChampionship(store, organizationID, championshipID); err != nil {
+			return err
+		}
+
+		member, err := store.ChampionshipMembers().Get(championshipID, userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return notFound(problem.CodeMemberNotFound, "Member not found")
+		}
+		if err != nil {
+			return failed("Failed to fetch member", err)
+		}
+
+		if member.Role.AtLeast(models.ChampionshipRoleOrganizer) && callerRole != models.ChampionshipRoleOwner {
+			return forbidden(problem.CodeOwnerRequired, "Only owners can remove owners and organizers")
+		}
+		if member.Role == models.ChampionshipRoleOwner {
+			if err := requireOtherChampionshipOwner(store, championshipID, member.UserID); err != nil {
+				return err
+			}
+		}
+
+		if err := store.ChampionshipMembers().Delete(&member); err != nil {
+			return failed("Failed to remove member", err)
+		}
+		return nil
+	})
+}
+
+// requireOtherChampionshipOwner makes sure a championship never loses its
+// last owner
+func requireOtherChampionshipOwner(store repository.Store, championshipID uint, userID uint) error {
+	owners, err := store.ChampionshipMembers().Owners(championshipID)
+	if err != nil {
+		return failed("Failed to check owners", err)
+	}
+	for _, owner := range owners {
+		if owner.UserID != userID {
+			return nil
+		}
+	}
+	return invalid(problem.CodeLastOwner, "A championship must keep at least one owner")
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"errors"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+)
+
+type OrganizationService interface {
+	// Memberships returns the organizations the user is a member of
+	Memberships(userID uint) ([]models.OrganizationMember, error)
+	// Create creates an organization owned by the user
+	Create(userID uint, organization models.Organization) (models.Organization, error)
+	// Members returns the members of an organization with their users
+	Members(actor Actor, id uint) ([]models.OrganizationMember, error)
+	// SetMember adds the user with the email address or changes the role of
+	// a member. Only owners grant or take away the owner role.
+	SetMember(actor Actor, id uint, email string, role models.OrganizationRole) (models.OrganizationMember, error)
+	// RemoveMember removes a member along with the member's championship
+	// roles in the organization
+	RemoveMember(actor Actor, id uint, userID uint) error
+}
+
+type organizationService struct {
+	store repository.Store
+}
+
+func NewOrganizationService(store repository.Store) OrganizationService {
+	return &organizationService{store: store}
+}
+
+// requireMembership fails unless the actor has at least the given role in
+// the organization. Non-members get the same answer as for a missing
+// organization.
+func requireMembership(actor Actor, id uint, min models.OrganizationRole) (models.OrganizationRole, error) {
+	role, err := actor.OrganizationRole(id)
+	if err != nil {
+		return role, failed("Failed to check permissions", err)
+	}
+	if role == "" {
+		return role, notFound(problem.CodeOrganizationNotFound, "Organization not found")
+	}
+	if !role.AtLeast(min) {
+		return role, forbidden(problem.CodeForbidden, "Insufficient permissions")
+	}
+	return role, nil
+}
+
+func (s *organizationService) Memberships(userID uint) ([]models.OrganizationMember, error) {
+	memberships, err := s.store.Organizations().Memberships(userID)
+	if err != nil {
+		return nil, failed("Failed to fetch organizations", err)
+	}
+	return memberships, nil
+}
+
+func (s *organizationService) Create(userID uint, organization models.Organization) (models.Organization, error) {
+	err := s.store.Transaction(func(store repository.Store) error {
+		if err := store.Organizations().Create(&organization); err != nil {
+			return err
+		}
+		return store.Organizations().SaveMember(&models.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         userID,
+			Role:           models.OrganizationRoleOwner,
+		})
+	})
+	if err != nil {
+		return organization, failed("Failed to create organization", err)
+	}
+	return organization, nil
+}
+
+func (s *organizationService) Members(actor Actor, id uint) ([]models.OrganizationMember, error) {
+	if _, err := requireMembership(actor, id, models.OrganizationRoleMember); err != nil {
+		return nil, err
+	}
+
+	members, err := s.store.Organizations().Members(id)
+	if err != nil {
+		return nil, failed("Failed to fetch members", err)
+	}
+	return members, nil
+}
+
+func (s *organizationService) SetMember(actor Actor, id uint, email string, role models.OrganizationRole) (models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	callerRole, err := requireMembership(actor, id, models.OrganizationRoleAdmin)
+	if err != nil {
+		return member, err
+	}
+	if role == models.OrganizationRoleOwner && callerRole != models.OrganizationRoleOwner {
+		return member, forbidden(problem.CodeOwnerRequired, "Only owners can grant the owner role")
+	}
+
+	err = s.store.Transaction(func(store repository.Store) error {
+		user, err := store.Users().FindByEmail(email)
+		if errors.Is(err, repository.ErrNotFound) {
+			return invalid(problem.CodeUserNotFound, "User not found")
+		}
+		if err != nil {
+			return failed("Failed to fetch user", err)
+		}
+
+		member, err = store.Organizations().GetMember(id, user.ID)
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			member = models.OrganizationMember{OrganizationID: id, UserID: user.ID}
+		case err != nil:
+			return failed("Failed to fetch member", err)
+		case member.Role == models.OrganizationRoleOwner && role != models.OrganizationRoleOwner:
+			if callerRole != models.OrganizationRoleOwner {
+				return forbidden(problem.CodeOwnerRequired, "Only owners can change the role of owners")
+			}
+			if err := requireOtherOrganizationOwner(store, id, user.ID); err != nil {
+				return err
+			}
+		}
+
+		member.Role = role
+		if err := store.Organizations().SaveMember(&member); err != nil {
+			return failed("Failed to save member", err)
+		}
+		member.User = &user
+		return nil
+	})
+	return member, err
+}
+
+func (s *organizationService) RemoveMember(actor Actor, id uint, userID uint) error {
+	callerRole, err := requireMembership(actor, id, models.OrganizationRoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Transaction(func(store repository.Store) error {
+		member, err := store.Organizations().GetMember(id, userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return notFound(problem.CodeMemberNotFound, "Member not found")
+		}
+		if err != nil {
+			return failed("Failed to fetch member", err)
+		}
+
+		if member.Role == models.OrganizationRoleOwner {
+			if callerRole != models.OrganizationRoleOwner {
+				return forbidden(problem.CodeOwnerRequired, "Only owners can remove owners")
+			}
+			if err := requireOtherOrganizationOwner(store, id, member.UserID); err != nil {
+				return err
+			}
+		}
+
+		if err := store.Organizations().DeleteMember(&member); err != nil {
+			return failed("Failed to remove member", err)
+		}
+		return nil
+	})
+}
+
+// requireOtherOrganizationOwner makes sure an organization never loses its
+// last owner
+func requireOtherOrganizationOwner(store repository.Store, id uint, userID uint) error {
+	owners, err := store.Organizations().Owners(id)
+	if err != nil {
+		return failed("Failed to check owners", err)
+	}
+	for _, owner := range owners {
+		if owner.UserID != userID {
+			return nil
+		}
+	}
+	return invalid(problem.CodeLastOwner, "An organization must keep at least one owner")
+}
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"scoretracker/backend/internal/achievements"
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+)
+
+type PlayerService interface {
+	// List returns a page of the players of the organization that pass the filter
+	List(organizationID uint, filter repository.PlayerFilter, options repository.ListOptions) ([]models.Player, repository.Page, error)
+	Get(organizationID uint, id uint) (models.Player, error)
+	// Create adds a player, optionally entering it into open championships
+	Create(organizationID uint, actor Actor, name string, championshipIDs []uint) (models.Player, error)
+	// Update renames the player if name is set and replaces its championships
+	// if championshipIDs is not nil. It fails if version is set and the
+	// player has another one.
+	Update(organizationID uint, actor Actor, id uint, version uint, name *string, championshipIDs []uint) (models.Player, error)
+	Delete(organizationID uint, actor Actor, id uint) error
+	// Achievements returns the badges the player earned, in the order they were awarded
+	Achievements(organizationID uint, id uint) ([]achievements.Earned, error)
+	// Calendar returns the player with its scheduled matches, only those of
+	// the given championship if set. The player must then belong to it.
+	Calendar(organizationID uint, id uint, championshipID *uint) (models.Player, []models.Match, error)
+	// Unavailability returns when the player cannot play, in the order it was added
+	Unavailability(organizationID uint, id uint) ([]models.PlayerUnavailability, error)
+	// AddUnavailability records when the player cannot play, either a weekday
+	// or a range of dates
+	AddUnavailability(organizationID uint, actor Actor, id uint, unavailability models.PlayerUnavailability) (models.PlayerUnavailability, error)
+	RemoveUnavailability(organizationID uint, actor Actor, id uint, unavailabilityID uint) error
+	// LinkUser links a member of the organization to the player. A linked
+	// user acts as the player in its championships, so only organization
+	// admins link users. A user is linked to one player at most, linking
+	// replaces any player the user was linked to before.
+	LinkUser(organizationID uint, actor Actor, id uint, userID uint) (models.User, error)
+	// UnlinkUser removes the link between the player and the user playing as them
+	UnlinkUser(organizationID uint, actor Actor, id uint) error
+}
+
+type playerService struct {
+	store repository.Store
+}
+
+func NewPlayerService(store repository.Store) PlayerService {
+	return &playerService{store: store}
+}
+
+func (s *playerService) load(organizationID uint, id uint) (models.Player, error) {
+	return loadPlayer(s.store, organizationID, id)
+}
+
+func loadPlayer(store repository.Store, organizationID uint, id uint) (models.Player, error) {
+	player, err := store.Players().Get(organizationID, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return player, notFound(problem.CodePlayerNotFound, "Player not found")
+	}
+	if err != nil {
+		return player, failed("Failed to fetch player", err)
+	}
+	return player, nil
+}
+
+func (s *playerService) List(organizationID uint, filter repository.PlayerFilter, options repository.ListOptions) ([]models.Player, repository.Page, error) {
+	if err := checkListOptions(options); err != nil {
+		return nil, repository.Page{}, err
+	}
+
+	players, page, err := s.store.Players().List(organizationID, filter, options)
+	if err != nil {
+		return nil, page, listFailed("Failed to fetch players", err)
+	}
+	return players, page, nil
+}
+
+func (s *playerService) Get(organizationID uint, id uint) (models.Player, error) {
+	return s.load(organizationID, id)
+}
+
+// openChampionships loads the championships and checks that players may
+// still be added to or removed from them. Within a transaction they stay
+// locked until it ends.
+func openChampionships(store repository.Store, organizationID uint, ids []uint, finalizedMessage string) ([]models.Championship, error) {
+	championships, err := store.Championships().FindByIDs(organizationID, ids)
+	if err != nil {
+		return nil, failed("Failed to verify championships", err)
+	}
+	if len(championships) != len(ids) {
+		return nil, invalidField("championship_ids", problem.FieldNotFound, "One or more championships not found")
+	}
+
+	for _, championship := range championships {
+		if championship.Status == models.ChampionshipStatusFinalized {
+			return nil, invalid(problem.CodeChampionshipFinalized, finalizedMessage)
+		}
+	}
+
+	return championships, nil
+}
+
+// requireOrganizer fails unless the actor organizes all championships
+func requireOrganizer(actor Actor, ids []uint) error {
+	for _, id := range ids {
+		if err := requireRole(actor, id, models.ChampionshipRoleOrganizer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// championshipChanges returns the championships the player joins and leaves
+// when its championships are replaced by ids
+func championshipChanges(player models.Player, ids []uint) (added []uint, removed []uint) {
+	current := make(map[uint]bool, len(player.Championships))
+	for _, championship := range player.Championships {
+		current[championship.ID] = true
+	}
+	requested := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+	}
+
+	for _, championship := range player.Championships {
+		if !requested[championship.ID] {
+			removed = append(removed, championship.ID)
+		}
+	}
+	for id := range requested {
+		if !current[id] {
+			added = append(added, id)
+		}
+	}
+	return added, removed
+}
+
+func (s *playerService) Create(organizationID uint, actor Actor, name string, championshipIDs []uint) (models.Player, error) {
+	player := models.Player{OrganizationID: organizationID, Name: name}
+	if name == "" {
+		return player, invalidField("name", problem.FieldRequired, "Name is required")
+	}
+
+	const finalizedMessage = "Cannot add players to a finalized championship"
+	if len(championshipIDs) > 0 {
+		if _, err := openChampionships(s.store, organizationID, championshipIDs, finalizedMessage); err != nil {
+			return player, err
+		}
+		if err := requireOrganizer(actor, championshipIDs); err != nil {
+			return player, err
+		}
+	}
+
+	if err := s.store.Transaction(func(store repository.Store) error {
+		// If championships are provided, verify and assign them
+		if len(championshipIDs) > 0 {
+			championships, err := openChampionships(store, organizationID, championshipIDs, finalizedMessage)
+			if err != nil {
+				return err
+			}
+			player.Championships = championships
+		}
+
+		if err := store.Players().Create(&player); err != nil {
+			return failed("Failed to create player", err)
+		}
+		return nil
+	}); err != nil {
+		return player, err
+	}
+
+	return s.load(organizationID, player.ID)
+}
+
+func (s *playerService) Update(organizationID uint, actor Actor, id uint, version uint, name *string, championshipIDs []uint) (models.Player, error) {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return player, err
+	}
+	if err := checkVersion(version, player.Version, "Player"); err != nil {
+		return player, err
+	}
+
+	if err := requirePlayerManager(actor, player); err != nil {
+		return player, err
+	}
+
+	authorized := make(map[uint]bool)
+	if championshipIDs != nil {
+		added, removed := championshipChanges(player, championshipIDs)
+		if err := checkChampionshipChanges(s.store, organizationID, added, removed); err != nil {
+			return player, err
+		}
+		for _, changed := range [][]uint{added, removed} {
+			if err := requireOrganizer(actor, changed); err != nil {
+				return player, err
+			}
+			for _, championshipID := range changed {
+				authorized[championshipID] = true
+			}
+		}
+	}
+
+	// The player and the championships it joins or leaves stay locked until
+	// the update is committed
+	if err := s.store.Transaction(func(store repository.Store) error {
+		player, err := loadPlayer(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, player.Version, "Player"); err != nil {
+			return err
+		}
+
+		if name != nil {
+			if err := store.Players().UpdateName(player.ID, *name); err != nil {
+				return failed("Failed to update player name", err)
+			}
+		}
+		if championshipIDs == nil {
+			return nil
+		}
+
+		added, removed := championshipChanges(player, championshipIDs)
+		for _, championshipID := range append(added, removed...) {
+			if !authorized[championshipID] {
+				return invalid(problem.CodeConcurrentUpdate, "Player was changed by another request, please retry")
+			}
+		}
+		if err := checkChampionshipChanges(store, organizationID, added, removed); err != nil {
+			return err
+		}
+
+		if err := store.Players().SetChampionships(player.ID, championshipIDs); err != nil {
+			return failed("Failed to update player championships", err)
+		}
+		return nil
+	}); err != nil {
+		return player, err
+	}
+
+	player, err = s.store.Players().Get(organizationID, id)
+	if err != nil {
+		return player, failed("Failed to reload player", err)
+	}
+	return player, nil
+}
+
+// checkChampionshipChanges checks that a player may join and leave the championships
+func checkChampionshipChanges(store repository.Store, organizationID uint, added []uint, removed []uint) error {
+	if len(removed) > 0 {
+		if _, err := openChampionships(store, organizationID, removed, "Cannot remove players from a finalized championship"); err != nil {
+			return err
+		}
+	}
+	if len(added) > 0 {
+		if _, err := openChampionships(store, organizationID, added, "Cannot add players to a finalized championship"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *playerService) Delete(organizationID uint, actor Actor, id uint) error {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requirePlayerManager(actor, player); err != nil {
+		return err
+	}
+
+	if err := s.store.Players().Delete(&player); err != nil {
+		return failed("Failed to delete player", err)
+	}
+	return nil
+}
+
+func (s *playerService) Achievements(organizationID uint, id uint) ([]achievements.Earned, error) {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	earned, err := s.store.Players().Achievements(player.ID)
+	if err != nil {
+		return nil, failed("Failed to fetch achievements", err)
+	}
+
+	result := make([]achievements.Earned, 0, len(earned))
+	for _, achievement := range earned {
+		result = append(result, achievements.Earned{
+			Achievement: achievement,
+			Description: achievements.Describe(achievement.Badge),
+		})
+	}
+	return result, nil
+}
+
+func (s *playerService) Calendar(organizationID uint, id uint, championshipID *uint) (models.Player, []models.Match, error) {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return player, nil, err
+	}
+
+	if championshipID != nil {
+		member, err := s.store.Players().InChampionship(player.ID, *championshipID)
+		if err != nil {
+			return player, nil, failed("Failed to fetch player", err)
+		}
+		if !member {
+			return player, nil, notFound(problem.CodePlayerNotFound, "Player not found")
+		}
+	}
+
+	matches, err := s.store.Matches().Scheduled(organizationID, repository.ScheduleFilter{ChampionshipID: championshipID, Player: player.Name})
+	if err != nil {
+		return player, nil, failed("Failed to fetch matches", err)
+	}
+	return player, matches, nil
+}
+
+func (s *playerService) Unavailability(organizationID uint, id uint) ([]models.PlayerUnavailability, error) {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	unavailability, err := s.store.Players().Unavailability([]uint{player.ID})
+	if err != nil {
+		return nil, failed("Failed to fetch unavailability", err)
+	}
+	return unavailability, nil
+}
+
+func (s *playerService) AddUnavailability(organizationID uint, actor Actor, id uint, unavailability models.PlayerUnavailability) (models.PlayerUnavailability, error) {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return unavailability, err
+	}
+
+	if err := requirePlayerManager(actor, player); err != nil {
+		return unavailability, err
+	}
+
+	unavailability.PlayerID = player.ID
+	switch unavailability.Kind {
+	case models.UnavailabilityKindWeekday:
+		if unavailability.Weekday == nil {
+			return unavailability, invalidField("weekday", problem.FieldRequired, "Weekday is required, between 0 (Sunday) and 6 (Saturday)")
+		}
+		unavailability.StartDate, unavailability.EndDate = "", ""
+	case models.UnavailabilityKindDateRange:
+		start, err := time.Parse(models.DateLayout, unavailability.StartDate)
+		if err != nil {
+			return unavailability, invalidField("start_date", problem.FieldInvalidFormat, "Start date must be in YYYY-MM-DD format")
+		}
+		end, err := time.Parse(models.DateLayout, unavailability.EndDate)
+		if err != nil {
+			return unavailability, invalidField("end_date", problem.FieldInvalidFormat, "End date must be in YYYY-MM-DD format")
+		}
+		if end.Before(start) {
+			return unavailability, invalidField("end_date", problem.FieldOutOfRange, "End date must not be before start date")
+		}
+		unavailability.Weekday = nil
+	}
+
+	if err := s.store.Players().CreateUnavailability(&unavailability); err != nil {
+		return unavailability, failed("Failed to create unavailability", err)
+	}
+	return unavailability, nil
+}
+
+func (s *playerService) RemoveUnavailability(organizationID uint, actor Actor, id uint, unavailabilityID uint) error {
+	player, err := s.load(organizationID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := requirePlayerManager(actor, player); err != nil {
+		return err
+	}
+
+	unavailability, err := s.store.Players().GetUnavailability(player.ID, unavailabilityID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return notFound(problem.CodeUnavailabilityNotFound, "Unavailability not found")
+	}
+	if err != nil {
+		return failed("Failed to fetch unavailability", err)
+	}
+
+	if err := s.store.Players().DeleteUnavailability(&unavailability); err != nil {
+		return failed("Failed to delete unavailability", err)
+	}
+	return nil
+}
+
+func (s *playerService) LinkUser(organizationID uint, actor Actor, id uint, userID uint) (models.User, error) {
+	var user models.User
+	if err := requireOrganizationRole(actor, organizationID, models.OrganizationRoleAdmin); err != nil {
+		return user, err
+	}
+
+	// The player stays locked, so two users cannot be linked to it at once
+	err := s.store.Transaction(func(store repository.Store) error {
+		player, err := loadPlayer(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := store.Organizations().GetMember(organizationID, userID); errors.Is(err, repository.ErrNotFound) {
+			return invalidField("user_id", problem.FieldNotFound, "User is not a member of the organization")
+		} else if err != nil {
+			return failed("Failed to verify user", err)
+		}
+
+		linked, err := store.Users().LinkedTo(player.ID)
+		if err != nil {
+			return failed("Failed to verify player", err)
+		}
+		for _, other := range linked {
+			if other.ID != userID {
+				return conflict(problem.CodePlayerAlreadyLinked, "Player is already linked to another user")
+			}
+		}
+
+		if err := store.Users().LinkPlayer(userID, player.ID); err != nil {
+			return failed("Failed to link user", err)
+		}
+		if user, err = store.Users().Get(userID); err != nil {
+			return failed("Failed to reload user", err)
+		}
+		return nil
+	})
+	return user, err
+}
+
+func (s *playerService) UnlinkUser(organizationID uint, actor Actor, id uint) error {
+	if err := requireOrganizationRole(actor, organizationID, models.OrganizationRoleAdmin); err != nil {
+		return err
+	}
+
+	return s.store.Transaction(func(store repository.Store) error {
+		player, err := loadPlayer(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+
+		unlinked, err := store.Users().UnlinkPlayer(player.ID)
+		if err != nil {
+			return failed("Failed to unlink user", err)
+		}
+		if !unlinked {
+			return invalid(problem.CodePlayerNotLinked, "Player is not linked to a user")
+		}
+		return nil
+	})
+}
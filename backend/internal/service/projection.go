@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/projections"
+	"scoretracker/backend/internal/repository"
+)
+
+type ProjectionService interface {
+	// Project simulates the remaining matches of a championship the given
+	// number of times. Match probabilities come from the players' historic
+	// results in the organization.
+	Project(organizationID uint, id uint, simulations int) (projections.Result, error)
+}
+
+type projectionService struct {
+	store repository.Store
+	// Projections with the default number of simulations, see projections.Cache
+	cache *projections.Cache
+}
+
+func NewProjectionService(store repository.Store, cache *projections.Cache) ProjectionService {
+	return &projectionService{store: store, cache: cache}
+}
+
+func (s *projectionService) Project(organizationID uint, id uint, simulations int) (projections.Result, error) {
+	championship, err := loadChampionship(s.store, organizationID, id, "Players")
+	if err != nil {
+		return projections.Result{}, err
+	}
+
+	matches, err := s.store.Matches().InChampionship(championship.ID)
+	if err != nil {
+		return projections.Result{}, failed("Failed to fetch matches", err)
+	}
+
+	// The number and the latest change of the finished matches of the
+	// organization stand for the history the strengths are based on
+	historyCount, latest, err := s.store.Matches().LatestFinished(organizationID)
+	if err != nil {
+		return projections.Result{}, failed("Failed to fetch match history", err)
+	}
+
+	// Any change to the players or matches of the championship, or to the
+	// history, produces a new version
+	hash := fnv.New64a()
+	fmt.Fprintf(hash, "%d", len(championship.Players))
+	for _, match := range matches {
+		fmt.Fprintf(hash, "/%d:%d", match.ID, match.UpdatedAt.UnixNano())
+	}
+	fmt.Fprintf(hash, "|%d:%d:%d", historyCount, latest.ID, latest.UpdatedAt.UnixNano())
+	version := strconv.FormatUint(hash.Sum64(), 16)
+
+	// Only the default number of simulations is cached
+	cached := simulations == projections.DefaultSimulations
+	if cached {
+		if result, ok := s.cache.Get(championship.ID, version); ok {
+			return result, nil
+		}
+	}
+
+	var finished, remaining []models.Match
+	for _, match := range matches {
+		if match.Status == models.MatchStatusFinished {
+			finished = append(finished, match)
+		} else {
+			remaining = append(remaining, match)
+		}
+	}
+
+	history, err := s.store.Matches().Finished(organizationID)
+	if err != nil {
+		return projections.Result{}, failed("Failed to fetch match history", err)
+	}
+
+	players := make([]string, 0, len(championship.Players))
+	for _, player := range championship.Players {
+		players = append(players, player.Name)
+	}
+
+	result := projections.Simulate(players, finished, remaining, projections.Strengths(history), simulations)
+	if cached {
+		s.cache.Put(championship.ID, version, result)
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"errors"
+
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/standings"
+)
+
+type SeasonService interface {
+	// List returns the seasons of the organization, newest first
+	List(organizationID uint) ([]models.Season, error)
+	// Get returns a season with its championships
+	Get(organizationID uint, id uint) (models.Season, error)
+	Create(organizationID uint, season models.Season) (models.Season, error)
+	// Update applies the changes of a request to the stored season and saves
+	// it. It fails if version is set and the season has another one.
+	Update(organizationID uint, id uint, version uint, apply func(*models.Season)) (models.Season, error)
+	// Delete removes a season, its championships no longer belong to one
+	Delete(organizationID uint, id uint) error
+	// Standings sums the weighted championship points of every player,
+	// leaving out each player's worst results as configured by drop_worst
+	Standings(organizationID uint, id uint) ([]standings.SeasonStanding, error)
+}
+
+type seasonService struct {
+	store repository.Store
+}
+
+func NewSeasonService(store repository.Store) SeasonService {
+	return &seasonService{store: store}
+}
+
+// loadSeason fetches a season and maps a missing one to a not found error
+func loadSeason(store repository.Store, organizationID uint, id uint, preloads ...string) (models.Season, error) {
+	season, err := store.Seasons().Get(organizationID, id, preloads...)
+	if errors.Is(err, repository.ErrNotFound) {
+		return season, notFound(problem.CodeSeasonNotFound, "Season not found")
+	}
+	if err != nil {
+		return season, failed("Failed to fetch season", err)
+	}
+	return season, nil
+}
+
+func (s *seasonService) List(organizationID uint) ([]models.Season, error) {
+	seasons, err := s.store.Seasons().List(organizationID)
+	if err != nil {
+		return nil, failed("Failed to fetch seasons", err)
+	}
+	return seasons, nil
+}
+
+func (s *seasonService) Get(organizationID uint, id uint) (models.Season, error) {
+	return loadSeason(s.store, organizationID, id, "Championships")
+}
+
+func (s *seasonService) Create(organizationID uint, season models.Season) (models.Season, error) {
+	season.OrganizationID = organizationID
+	// Championships join seasons one by one
+	season.Championships = nil
+
+	if err := s.store.Seasons().Create(&season); err != nil {
+		return season, failed("Failed to create season", err)
+	}
+	return season, nil
+}
+
+func (s *seasonService) Update(organizationID uint, id uint, version uint, apply func(*models.Season)) (models.Season, error) {
+	var season models.Season
+	err := s.store.Transaction(func(store repository.Store) error {
+		var err error
+		season, err = loadSeason(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(version, season.Version, "Season"); err != nil {
+			return err
+		}
+
+		apply(&season)
+		season.Version++
+
+		if err := store.Seasons().Save(&season); err != nil {
+			return failed("Failed to update season", err)
+		}
+		return nil
+	})
+	return season, err
+}
+
+func (s *seasonService) Delete(organizationID uint, id uint) error {
+	return s.store.Transaction(func(store repository.Store) error {
+		season, err := loadSeason(store, organizationID, id)
+		if err != nil {
+			return err
+		}
+		if err := store.Seasons().Delete(&season); err != nil {
+			return failed("Failed to delete season", err)
+		}
+		return nil
+	})
+}
+
+func (s *seasonService) Standings(organizationID uint, id uint) ([]standings.SeasonStanding, error) {
+	season, err := loadSeason(s.store, organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	championships, err := s.store.Championships().InSeason(season.ID)
+	if err != nil {
+		return nil, failed("Failed to fetch championships", err)
+	}
+
+	results := make([]standings.ChampionshipResult, 0, len(championships))
+	for _, championship := range championships {
+		matches, err := s.store.Matches().FinishedInChampionship(championship.ID, nil)
+		if err != nil {
+			return nil, failed("Failed to fetch matches", err)
+		}
+
+		// Only players of the championship get a result, including those without points
+		points := standings.Points(matches)
+		participants := make(map[string]int, len(championship.Players))
+		for _, player := range championship.Players {
+			participants[player.Name] = points[player.Name]
+		}
+
+		results = append(results, standings.ChampionshipResult{
+			ChampionshipID:   championship.ID,
+			ChampionshipName: championship.Name,
+			Weight:           championship.SeasonWeight,
+			Points:           participants,
+		})
+	}
+
+	return standings.Season(results, season.DropWorst), nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"scoretracker/backend/internal/models"
+	"scoretracker/backend/internal/problem"
+	"scoretracker/backend/internal/repository"
+	"scoretracker/backend/internal/stats"
+)
+
+type StatsService interface {
+	// PlayerStats computes the statistics of a player from its finished
+	// matches that pass the filter
+	PlayerStats(organizationID uint, id uint, filter repository.ResultFilter) (models.Player, stats.PlayerStats, error)
+	// HeadToHead compares two players by the finished matches between them
+	// that pass the filter
+	HeadToHead(organizationID uint, id uint, opponentID uint, filter repository.ResultFilter) (HeadToHead, error)
+	// Records returns the hall of fame of the organization, each record with
+	// the match that set it, in the order of models.RecordKinds
+	Records(organizationID uint) ([]models.Record, error)
+}
+
+// HeadToHead is the record of a player against an opponent, from the
+// player's point of view, with the matches it is based on, newest first
+type HeadToHead struct {
+	Player   models.Player
+	Opponent models.Player
+	Record   stats.HeadToHead
+	Matches  []models.Match
+}
+
+type statsService struct {
+	store repository.Store
+}
+
+func NewStatsService(store repository.Store) StatsService {
+	return &statsService{store: store}
+}
+
+func (s *statsService) PlayerStats(organizationID uint, id uint, filter repository.ResultFilter) (models.Player, stats.PlayerStats, error) {
+	player, err := loadPlayer(s.store, organizationID, id)
+	if err != nil {
+		return player, stats.PlayerStats{}, err
+	}
+
+	filter.Player, filter.Opponent = player.Name, ""
+	matches, err := s.store.Matches().Results(organizationID, filter)
+	if err != nil {
+		return player, stats.PlayerStats{}, failed("Failed to fetch matches", err)
+	}
+
+	return player, stats.Compute(player.Name, matches), nil
+}
+
+func (s *statsService) HeadToHead(organizationID uint, id uint, opponentID uint, filter repository.ResultFilter) (HeadToHead, error) {
+	var result HeadToHead
+	if id == opponentID {
+		return result, invalid(problem.CodeSamePlayers, "A player cannot be compared with themselves")
+	}
+
+	var err error
+	if result.Player, err = loadPlayer(s.store, organizationID, id); err != nil {
+		return result, err
+	}
+	if result.Opponent, err = loadPlayer(s.store, organizationID, opponentID); err != nil {
+		return result, err
+	}
+
+	filter.Player, filter.Opponent = result.Player.Name, result.Opponent.Name
+	matches, err := s.store.Matches().Results(organizationID, filter)
+	if err != nil {
+		return result, failed("Failed to fetch matches", err)
+	}
+	result.Record = stats.CompareHeadToHead(result.Player.Name, result.Opponent.Name, matches)
+
+	// Newest first for display
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	result.Matches = matches
+
+	return result, nil
+}
+
+func (s *statsService) Records(organizationID uint) ([]models.Record, error) {
+	records, err := s.store.Records().List(organizationID)
+	if err != nil {
+		return nil, failed("Failed to fetch records", err)
+	}
+
+	// Keep the order stable for display
+	ordered := make([]models.Record, 0, len(records))
+	for _, kind := range models.RecordKinds {
+		for _, record := range records {
+			if record.Kind == kind {
+				ordered = append(ordered, record)
+			}
+		}
+	}
+	return ordered, nil
+}
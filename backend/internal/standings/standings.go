@@ -0,0 +1,301 @@
+package standings
+
+import (
+	"sort"
+
+	"scoretracker/backend/internal/models"
+)
+
+// Points awarded per match result
+const (
+	PointsWin  = 3
+	PointsDraw = 1
+	PointsLoss = 0
+)
+
+// Points sums the points every player earned in the given finished matches
+func Points(matches []models.Match) map[string]int {
+	points := make(map[string]int)
+
+	for _, match := range matches {
+		if match.Winner == nil {
+			points[match.Player1] += PointsDraw
+			points[match.Player2] += PointsDraw
+		} else if *match.Winner == match.Player1 {
+			points[match.Player1] += PointsWin
+			points[match.Player2] += PointsLoss
+		} else if *match.Winner == match.Player2 {
+			points[match.Player1] += PointsLoss
+			points[match.Player2] += PointsWin
+		}
+	}
+
+	return points
+}
+
+// Order sorts players by points. Players with equal points are ordered by the
+// points they earned in the matches among themselves (head-to-head), then by name.
+func Order(players []string, points map[string]int, matches []models.Match) []string {
+	ordered := append([]string(nil), players...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return points[ordered[i]] > points[ordered[j]]
+	})
+
+	for start := 0; start < len(ordered); {
+		end := start + 1
+		for end < len(ordered) && points[ordered[end]] == points[ordered[start]] {
+			end++
+		}
+
+		if end-start > 1 {
+			tied := make(map[string]bool, end-start)
+			for _, player := range ordered[start:end] {
+				tied[player] = true
+			}
+
+			var among []models.Match
+			for _, match := range matches {
+				if tied[match.Player1] && tied[match.Player2] {
+					among = append(among, match)
+				}
+			}
+			headToHead := Points(among)
+
+			group := ordered[start:end]
+			sort.SliceStable(group, func(i, j int) bool {
+				if headToHead[group[i]] != headToHead[group[j]] {
+					return headToHead[group[i]] > headToHead[group[j]]
+				}
+				return group[i] < group[j]
+			})
+		}
+
+		start = end
+	}
+
+	return ordered
+}
+
+type Standing struct {
+	Position   int    `json:"position"`
+	PlayerName string `json:"player_name"`
+	Points     int    `json:"points"`
+}
+
+// Table builds the standings of the given players from finished matches,
+// sorted by points with ties broken head-to-head
+func Table(players []string, matches []models.Match) []Standing {
+	points := Points(matches)
+
+	table := make([]Standing, 0, len(players))
+	for i, name := range Order(players, points, matches) {
+		table = append(table, Standing{
+			Position:   i + 1,
+			PlayerName: name,
+			Points:     points[name],
+		})
+	}
+
+	return table
+}
+
+// Row is a standing together with the results it was built from
+type Row struct {
+	Standing
+	Played       int `json:"played"`
+	Won          int `json:"won"`
+	Drawn        int `json:"drawn"`
+	Lost         int `json:"lost"`
+	ScoreFor     int `json:"score_for"`
+	ScoreAgainst int `json:"score_against"`
+}
+
+// Rows builds the same table as Table and counts the results of every player
+func Rows(players []string, matches []models.Match) []Row {
+	table := Table(players, matches)
+
+	rows := make([]Row, len(table))
+	index := make(map[string]int, len(table))
+	for i, standing := range table {
+		rows[i].Standing = standing
+		index[standing.PlayerName] = i
+	}
+
+	count := func(player string, scored int, conceded int, winner *string) {
+		i, ok := index[player]
+		if !ok {
+			return
+		}
+		row := &rows[i]
+		row.Played++
+		row.ScoreFor += scored
+		row.ScoreAgainst += conceded
+		switch {
+		case winner == nil:
+			row.Drawn++
+		case *winner == player:
+			row.Won++
+		default:
+			row.Lost++
+		}
+	}
+	for _, match := range matches {
+		count(match.Player1, match.Player1Score, match.Player2Score, match.Winner)
+		count(match.Player2, match.Player2Score, match.Player1Score, match.Winner)
+	}
+
+	return rows
+}
+
+// Completion is a finalized championship in which every participant has played
+// every other participant and all matches are finished
+type Completion struct {
+	Championship models.Championship
+	Matches      []models.Match
+	LastMatch    models.Match
+	// Participants ordered by final position
+	Ranking []string
+}
+
+// Completed returns the completed championships in the order they were
+// completed. Championships need their Players preloaded; matches may contain
+// matches of any status and championship.
+func Completed(championships []models.Championship, matches []models.Match) []Completion {
+	byChampionship := make(map[uint][]models.Match)
+	for _, match := range matches {
+		byChampionship[match.ChampionshipID] = append(byChampionship[match.ChampionshipID], match)
+	}
+
+	var completed []Completion
+	for _, championship := range championships {
+		championshipMatches := byChampionship[championship.ID]
+		if championship.Status != models.ChampionshipStatusFinalized || len(championship.Players) < 2 {
+			continue
+		}
+
+		finished := true
+		var lastMatch models.Match
+		played := make(map[[2]string]bool)
+		for _, match := range championshipMatches {
+			if match.Status != models.MatchStatusFinished || match.FinishedAt == nil {
+				finished = false
+				break
+			}
+			if lastMatch.FinishedAt == nil || match.FinishedAt.After(*lastMatch.FinishedAt) {
+				lastMatch = match
+			}
+			played[[2]string{match.Player1, match.Player2}] = true
+			played[[2]string{match.Player2, match.Player1}] = true
+		}
+
+		players := make([]string, 0, len(championship.Players))
+		for i, player := range championship.Players {
+			players = append(players, player.Name)
+			for _, other := range championship.Players[i+1:] {
+				if !played[[2]string{player.Name, other.Name}] {
+					finished = false
+				}
+			}
+		}
+		if !finished {
+			continue
+		}
+
+		completed = append(completed, Completion{
+			Championship: championship,
+			Matches:      championshipMatches,
+			LastMatch:    lastMatch,
+			Ranking:      Order(players, Points(championshipMatches), championshipMatches),
+		})
+	}
+
+	sort.Slice(completed, func(i, j int) bool {
+		a, b := completed[i].LastMatch, completed[j].LastMatch
+		if !a.FinishedAt.Equal(*b.FinishedAt) {
+			return a.FinishedAt.Before(*b.FinishedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	return completed
+}
+
+// ChampionshipResult holds the points of every participant of one championship
+type ChampionshipResult struct {
+	ChampionshipID   uint
+	ChampionshipName string
+	Weight           float64
+	Points           map[string]int
+}
+
+// SeasonResult is the contribution of one championship to a player's season total
+type SeasonResult struct {
+	ChampionshipID   uint    `json:"championship_id"`
+	ChampionshipName string  `json:"championship_name"`
+	Points           int     `json:"points"`
+	Weight           float64 `json:"weight"`
+	WeightedPoints   float64 `json:"weighted_points"`
+	Dropped          bool    `json:"dropped"`
+}
+
+type SeasonStanding struct {
+	PlayerName string         `json:"player_name"`
+	Points     float64        `json:"points"`
+	Counted    int            `json:"counted"`
+	Results    []SeasonResult `json:"results"`
+}
+
+// Season aggregates championship results into season standings. Each player's
+// worst dropWorst results are dropped, but at least one result always counts.
+func Season(results []ChampionshipResult, dropWorst int) []SeasonStanding {
+	byPlayer := make(map[string][]SeasonResult)
+	for _, result := range results {
+		for player, points := range result.Points {
+			byPlayer[player] = append(byPlayer[player], SeasonResult{
+				ChampionshipID:   result.ChampionshipID,
+				ChampionshipName: result.ChampionshipName,
+				Points:           points,
+				Weight:           result.Weight,
+				WeightedPoints:   float64(points) * result.Weight,
+			})
+		}
+	}
+
+	standings := make([]SeasonStanding, 0, len(byPlayer))
+	for player, playerResults := range byPlayer {
+		// Mark the worst results as dropped, oldest championship first on ties
+		order := make([]int, len(playerResults))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return playerResults[order[a]].WeightedPoints < playerResults[order[b]].WeightedPoints
+		})
+
+		drop := dropWorst
+		if drop > len(playerResults)-1 {
+			drop = len(playerResults) - 1
+		}
+		for _, i := range order[:max(drop, 0)] {
+			playerResults[i].Dropped = true
+		}
+
+		standing := SeasonStanding{PlayerName: player, Results: playerResults}
+		for _, result := range playerResults {
+			if !result.Dropped {
+				standing.Points += result.WeightedPoints
+				standing.Counted++
+			}
+		}
+		standings = append(standings, standing)
+	}
+
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Points != standings[j].Points {
+			return standings[i].Points > standings[j].Points
+		}
+		return standings[i].PlayerName < standings[j].PlayerName
+	})
+
+	return standings
+}
@@ -0,0 +1,211 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"scoretracker/backend/internal/models"
+)
+
+type Outcome string
+
+const (
+	OutcomeWin  Outcome = "W"
+	OutcomeDraw Outcome = "D"
+	OutcomeLoss Outcome = "L"
+)
+
+// FormLength is the number of results shown as recent form
+const FormLength = 5
+
+// OutcomeFor returns the result of a finished match from the player's point of view
+func OutcomeFor(match models.Match, player string) Outcome {
+	switch {
+	case match.Winner == nil:
+		return OutcomeDraw
+	case *match.Winner == player:
+		return OutcomeWin
+	default:
+		return OutcomeLoss
+	}
+}
+
+// Scores returns the player's score and the opponent's score
+func Scores(match models.Match, player string) (scoreFor int, scoreAgainst int) {
+	if match.Player1 == player {
+		return match.Player1Score, match.Player2Score
+	}
+	return match.Player2Score, match.Player1Score
+}
+
+// Opponent returns the other player of the match
+func Opponent(match models.Match, player string) string {
+	if match.Player1 == player {
+		return match.Player2
+	}
+	return match.Player1
+}
+
+type Record struct {
+	Played  int     `json:"played"`
+	Wins    int     `json:"wins"`
+	Draws   int     `json:"draws"`
+	Losses  int     `json:"losses"`
+	WinRate float64 `json:"win_rate"`
+}
+
+func (r *Record) add(outcome Outcome) {
+	r.Played++
+	switch outcome {
+	case OutcomeWin:
+		r.Wins++
+	case OutcomeDraw:
+		r.Draws++
+	case OutcomeLoss:
+		r.Losses++
+	}
+	r.WinRate = float64(r.Wins) / float64(r.Played)
+}
+
+type FormEntry struct {
+	MatchID    uint       `json:"match_id"`
+	Opponent   string     `json:"opponent"`
+	Result     Outcome    `json:"result"`
+	Score      string     `json:"score"`
+	FinishedAt *time.Time `json:"finished_at"`
+}
+
+type GameStats struct {
+	Game string `json:"game"`
+	Record
+}
+
+type PlayerStats struct {
+	Record
+	AverageScoreFor     float64     `json:"average_score_for"`
+	AverageScoreAgainst float64     `json:"average_score_against"`
+	CurrentWinStreak    int         `json:"current_win_streak"`
+	LongestWinStreak    int         `json:"longest_win_streak"`
+	RecentForm          []FormEntry `json:"recent_form"`
+	Games               []GameStats `json:"games"`
+}
+
+// Compute builds the statistics of a player from finished matches ordered
+// from oldest to newest
+func Compute(player string, matches []models.Match) PlayerStats {
+	stats := PlayerStats{RecentForm: []FormEntry{}, Games: []GameStats{}}
+	games := make(map[string]*Record)
+	totalFor, totalAgainst := 0, 0
+	streak := 0
+
+	for _, match := range matches {
+		outcome := OutcomeFor(match, player)
+		stats.add(outcome)
+
+		if games[match.Game] == nil {
+			games[match.Game] = &Record{}
+		}
+		games[match.Game].add(outcome)
+
+		scoreFor, scoreAgainst := Scores(match, player)
+		totalFor += scoreFor
+		totalAgainst += scoreAgainst
+
+		if outcome == OutcomeWin {
+			streak++
+		} else {
+			streak = 0
+		}
+		if streak > stats.LongestWinStreak {
+			stats.LongestWinStreak = streak
+		}
+	}
+	stats.CurrentWinStreak = streak
+
+	if stats.Played > 0 {
+		stats.AverageScoreFor = float64(totalFor) / float64(stats.Played)
+		stats.AverageScoreAgainst = float64(totalAgainst) / float64(stats.Played)
+	}
+
+	// Most recent result first
+	for i := len(matches) - 1; i >= 0 && len(stats.RecentForm) < FormLength; i-- {
+		match := matches[i]
+		scoreFor, scoreAgainst := Scores(match, player)
+		stats.RecentForm = append(stats.RecentForm, FormEntry{
+			MatchID:    match.ID,
+			Opponent:   Opponent(match, player),
+			Result:     OutcomeFor(match, player),
+			Score:      fmt.Sprintf("%d:%d", scoreFor, scoreAgainst),
+			FinishedAt: match.FinishedAt,
+		})
+	}
+
+	for game, record := range games {
+		stats.Games = append(stats.Games, GameStats{Game: game, Record: *record})
+	}
+	sort.Slice(stats.Games, func(i, j int) bool {
+		return stats.Games[i].Game < stats.Games[j].Game
+	})
+
+	return stats
+}
+
+// Margin identifies the match a player won by the largest score difference
+type Margin struct {
+	MatchID uint   `json:"match_id"`
+	Margin  int    `json:"margin"`
+	Score   string `json:"score"`
+}
+
+type HeadToHead struct {
+	Played            int     `json:"played"`
+	Wins              int     `json:"wins"`
+	Draws             int     `json:"draws"`
+	Losses            int     `json:"losses"`
+	ScoreFor          int     `json:"score_for"`
+	ScoreAgainst      int     `json:"score_against"`
+	MostRecentMatchID *uint   `json:"most_recent_match_id"`
+	BiggestWin        *Margin `json:"biggest_win"`
+	BiggestLoss       *Margin `json:"biggest_loss"`
+}
+
+// CompareHeadToHead summarizes the finished matches between player and
+// opponent from the player's point of view. Matches are ordered from oldest to
+// newest; matches against anyone else are ignored.
+func CompareHeadToHead(player string, opponent string, matches []models.Match) HeadToHead {
+	var h2h HeadToHead
+
+	for _, match := range matches {
+		if Opponent(match, player) != opponent || (match.Player1 != player && match.Player2 != player) {
+			continue
+		}
+
+		h2h.Played++
+		scoreFor, scoreAgainst := Scores(match, player)
+		h2h.ScoreFor += scoreFor
+		h2h.ScoreAgainst += scoreAgainst
+		matchID := match.ID
+		h2h.MostRecentMatchID = &matchID
+
+		margin := &Margin{MatchID: match.ID, Score: fmt.Sprintf("%d:%d", scoreFor, scoreAgainst)}
+		switch OutcomeFor(match, player) {
+		case OutcomeWin:
+			h2h.Wins++
+			margin.Margin = scoreFor - scoreAgainst
+			if h2h.BiggestWin == nil || margin.Margin > h2h.BiggestWin.Margin {
+				h2h.BiggestWin = margin
+			}
+		case OutcomeDraw:
+			h2h.Draws++
+		case OutcomeLoss:
+			h2h.Losses++
+			margin.Margin = scoreAgainst - scoreFor
+			if h2h.BiggestLoss == nil || margin.Margin > h2h.BiggestLoss.Margin {
+				h2h.BiggestLoss = margin
+			}
+		}
+	}
+
+	return h2h
+}
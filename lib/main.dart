@@ -1,12 +1,23 @@
 import 'package:flutter/material.dart';
 import 'package:provider/provider.dart';
+import 'services/api_service.dart';
 import 'views/home_view.dart';
+import 'views/login_view.dart';
 import 'theme/app_theme.dart';
 import 'viewmodels/championship_viewmodel.dart';
 import 'viewmodels/player_viewmodel.dart';
 import 'viewmodels/match_viewmodel.dart';
 
+final navigatorKey = GlobalKey<NavigatorState>();
+
 void main() {
+  // Back to the login screen once the refresh token is no longer accepted
+  ApiService.onSessionExpired = () {
+    navigatorKey.currentState?.pushAndRemoveUntil(
+      MaterialPageRoute(builder: (context) => const LoginView()),
+      (route) => false,
+    );
+  };
   runApp(const ScoreTrackerApp());
 }
 
@@ -23,9 +34,10 @@ class ScoreTrackerApp extends StatelessWidget {
       ],
       child: MaterialApp(
         title: 'Score Tracker',
+        navigatorKey: navigatorKey,
         theme: AppTheme.lightTheme,
         debugShowCheckedModeBanner: false,
-        home: const HomeView(),
+        home: ApiService.isLoggedIn ? const HomeView() : const LoginView(),
       ),
     );
   }
@@ -34,8 +34,12 @@ class Match {
       switch (status) {
         case 'started':
           return MatchStatus.started;
+        case 'pending_confirmation':
+          return MatchStatus.pendingConfirmation;
         case 'finished':
           return MatchStatus.finished;
+        case 'disputed':
+          return MatchStatus.disputed;
         default:
           return MatchStatus.pending;
       }
@@ -69,12 +73,16 @@ class Match {
   Map<String, dynamic> toJson() {
     String statusString() {
       switch (status) {
+        case MatchStatus.pending:
+          return 'pending';
         case MatchStatus.started:
           return 'started';
+        case MatchStatus.pendingConfirmation:
+          return 'pending_confirmation';
         case MatchStatus.finished:
           return 'finished';
-        default:
-          return 'pending';
+        case MatchStatus.disputed:
+          return 'disputed';
       }
     }
 
@@ -95,6 +103,10 @@ class Match {
 enum MatchStatus {
   pending,
   started,
+  // Reported by a player, waiting for the opponent to confirm
+  pendingConfirmation,
   finished,
+  // The opponent disputed the reported result, an organizer resolves it
+  disputed,
 }
 
@@ -7,9 +7,105 @@ import '../models/player.dart';
 class ApiService {
   static const String baseUrl = String.fromEnvironment(
     'API_BASE_URL',
-    defaultValue: 'http://localhost:8080/api',
+    defaultValue: 'http://localhost:8080/api/v1',
   );
 
+  // Access token sent with every request. Set by login, or at build time
+  // with --dart-define=API_TOKEN=... to skip the login screen.
+  static String accessToken = const String.fromEnvironment('API_TOKEN');
+
+  // Refresh token from login. Access tokens expire after 15 minutes, a
+  // request answered with 401 gets a new one with it and is sent again.
+  static String refreshToken = '';
+
+  // Organization the requests act in, required if the user belongs to
+  // several. 0 lets the server pick the only organization of the user.
+  static int organizationId = const int.fromEnvironment('API_ORGANIZATION_ID');
+
+  // Called when the session cannot be refreshed and the user has to log in
+  // again
+  static void Function()? onSessionExpired;
+
+  // Refresh in progress, shared by requests that fail at the same time since
+  // each refresh token can only be used once
+  static Future<bool>? _refreshing;
+
+  static bool get isLoggedIn => accessToken.isNotEmpty;
+
+  static Map<String, String> _headers({bool json = false}) {
+    return {
+      if (json) 'Content-Type': 'application/json',
+      if (accessToken.isNotEmpty) 'Authorization': 'Bearer $accessToken',
+      if (organizationId != 0) 'X-Organization-ID': '$organizationId',
+    };
+  }
+
+  // Sends a request, refreshing the access token once if it has expired.
+  // request must build its headers when called, so the retry sends the new
+  // token.
+  static Future<http.Response> _send(Future<http.Response> Function() request) async {
+    final response = await request();
+    if (response.statusCode != 401) {
+      return response;
+    }
+
+    _refreshing ??= _refresh().whenComplete(() => _refreshing = null);
+    if (!await _refreshing!) {
+      return response;
+    }
+    return request();
+  }
+
+  static Future<bool> _refresh() async {
+    if (refreshToken.isNotEmpty) {
+      try {
+        final response = await http.post(
+          Uri.parse('$baseUrl/auth/refresh'),
+          headers: {'Content-Type': 'application/json'},
+          body: json.encode({'refresh_token': refreshToken}),
+        );
+
+        if (response.statusCode == 200) {
+          _storeTokens(json.decode(response.body));
+          return true;
+        }
+      } catch (_) {
+        // Treated like a rejected refresh token below
+      }
+    }
+
+    accessToken = '';
+    refreshToken = '';
+    onSessionExpired?.call();
+    return false;
+  }
+
+  static void _storeTokens(Map<String, dynamic> tokens) {
+    accessToken = tokens['access_token'];
+    refreshToken = tokens['refresh_token'];
+  }
+
+  static Future<void> login(String email, String password) async {
+    final http.Response response;
+    try {
+      response = await http.post(
+        Uri.parse('$baseUrl/auth/login'),
+        headers: _headers(json: true),
+        body: json.encode({'email': email, 'password': password}),
+      );
+    } catch (e) {
+      throw Exception('Error logging in: $e');
+    }
+
+    if (response.statusCode == 200) {
+      _storeTokens(json.decode(response.body));
+    } else if (response.statusCode == 401) {
+      throw Exception('Invalid email or password');
+    } else {
+      throw Exception('Login failed: ${response.statusCode}');
+    }
+  }
+
   static Future<Map<String, dynamic>> healthCheck() async {
     try {
       final response = await http.get(Uri.parse('$baseUrl/health'));
@@ -31,7 +127,7 @@ class ApiService {
       if (championshipId != null) {
         url += '?championship_id=$championshipId';
       }
-      final response = await http.get(Uri.parse(url));
+      final response = await _send(() => http.get(Uri.parse(url), headers: _headers()));
       
       if (response.statusCode == 200) {
         final List<dynamic> data = json.decode(response.body);
@@ -46,7 +142,7 @@ class ApiService {
 
   static Future<Match> getMatch(int id) async {
     try {
-      final response = await http.get(Uri.parse('$baseUrl/matches/$id'));
+      final response = await _send(() => http.get(Uri.parse('$baseUrl/matches/$id'), headers: _headers()));
       
       if (response.statusCode == 200) {
         return Match.fromJson(json.decode(response.body));
@@ -62,11 +158,11 @@ class ApiService {
 
   static Future<Match> createMatch(Match match) async {
     try {
-      final response = await http.post(
+      final response = await _send(() => http.post(
         Uri.parse('$baseUrl/matches'),
-        headers: {'Content-Type': 'application/json'},
+        headers: _headers(json: true),
         body: json.encode(match.toJson()),
-      );
+      ));
       
       if (response.statusCode == 201) {
         return Match.fromJson(json.decode(response.body));
@@ -81,7 +177,7 @@ class ApiService {
 
   static Future<Match> startMatch(int id) async {
     try {
-      final response = await http.post(Uri.parse('$baseUrl/matches/$id/start'));
+      final response = await _send(() => http.post(Uri.parse('$baseUrl/matches/$id/start'), headers: _headers()));
       
       if (response.statusCode == 200) {
         return Match.fromJson(json.decode(response.body));
@@ -96,14 +192,14 @@ class ApiService {
 
   static Future<Match> updateMatchScore(int id, int player1Score, int player2Score) async {
     try {
-      final response = await http.put(
+      final response = await _send(() => http.put(
         Uri.parse('$baseUrl/matches/$id/score'),
-        headers: {'Content-Type': 'application/json'},
+        headers: _headers(json: true),
         body: json.encode({
           'player1_score': player1Score,
           'player2_score': player2Score,
         }),
-      );
+      ));
       
       if (response.statusCode == 200) {
         return Match.fromJson(json.decode(response.body));
@@ -118,7 +214,7 @@ class ApiService {
 
   static Future<Match> finishMatch(int id) async {
     try {
-      final response = await http.post(Uri.parse('$baseUrl/matches/$id/finish'));
+      final response = await _send(() => http.post(Uri.parse('$baseUrl/matches/$id/finish'), headers: _headers()));
       
       if (response.statusCode == 200) {
         return Match.fromJson(json.decode(response.body));
@@ -135,7 +231,7 @@ class ApiService {
   // Championship methods
   static Future<List<Championship>> getAllChampionships() async {
     try {
-      final response = await http.get(Uri.parse('$baseUrl/championships'));
+      final response = await _send(() => http.get(Uri.parse('$baseUrl/championships'), headers: _headers()));
       
       if (response.statusCode == 200) {
         final List<dynamic> data = json.decode(response.body);
@@ -150,7 +246,7 @@ class ApiService {
 
   static Future<Championship> getChampionship(int id) async {
     try {
-      final response = await http.get(Uri.parse('$baseUrl/championships/$id'));
+      final response = await _send(() => http.get(Uri.parse('$baseUrl/championships/$id'), headers: _headers()));
       
       if (response.statusCode == 200) {
         return Championship.fromJson(json.decode(response.body));
@@ -166,11 +262,11 @@ class ApiService {
 
   static Future<Championship> createChampionship(Championship championship) async {
     try {
-      final response = await http.post(
+      final response = await _send(() => http.post(
         Uri.parse('$baseUrl/championships'),
-        headers: {'Content-Type': 'application/json'},
+        headers: _headers(json: true),
         body: json.encode(championship.toJson()),
-      );
+      ));
       
       if (response.statusCode == 201) {
         return Championship.fromJson(json.decode(response.body));
@@ -185,7 +281,7 @@ class ApiService {
 
   static Future<Championship> finalizeChampionship(int id) async {
     try {
-      final response = await http.post(Uri.parse('$baseUrl/championships/$id/finalize'));
+      final response = await _send(() => http.post(Uri.parse('$baseUrl/championships/$id/finalize'), headers: _headers()));
       
       if (response.statusCode == 200) {
         return Championship.fromJson(json.decode(response.body));
@@ -200,7 +296,7 @@ class ApiService {
 
   static Future<Map<String, dynamic>> generateRoundRobinMatches(int championshipId) async {
     try {
-      final response = await http.post(Uri.parse('$baseUrl/championships/$championshipId/generate-matches'));
+      final response = await _send(() => http.post(Uri.parse('$baseUrl/championships/$championshipId/generate-matches'), headers: _headers()));
       
       if (response.statusCode == 201) {
         return json.decode(response.body);
@@ -215,7 +311,7 @@ class ApiService {
 
   static Future<List<Map<String, dynamic>>> getStandings(int championshipId) async {
     try {
-      final response = await http.get(Uri.parse('$baseUrl/championships/$championshipId/standings'));
+      final response = await _send(() => http.get(Uri.parse('$baseUrl/championships/$championshipId/standings'), headers: _headers()));
       
       if (response.statusCode == 200) {
         final List<dynamic> data = json.decode(response.body);
@@ -230,7 +326,7 @@ class ApiService {
 
   static Future<void> deleteChampionship(int id) async {
     try {
-      final response = await http.delete(Uri.parse('$baseUrl/championships/$id'));
+      final response = await _send(() => http.delete(Uri.parse('$baseUrl/championships/$id'), headers: _headers()));
       
       if (response.statusCode != 200 && response.statusCode != 204) {
         throw Exception('Failed to delete championship: ${response.statusCode}');
@@ -247,7 +343,7 @@ class ApiService {
       if (championshipId != null) {
         url += '?championship_id=$championshipId';
       }
-      final response = await http.get(Uri.parse(url));
+      final response = await _send(() => http.get(Uri.parse(url), headers: _headers()));
       
       if (response.statusCode == 200) {
         final List<dynamic> data = json.decode(response.body);
@@ -267,11 +363,11 @@ class ApiService {
         'championship_ids': player.championships?.map((c) => c.id).where((id) => id != null).toList() ?? [],
       };
       
-      final response = await http.post(
+      final response = await _send(() => http.post(
         Uri.parse('$baseUrl/players'),
-        headers: {'Content-Type': 'application/json'},
+        headers: _headers(json: true),
         body: json.encode(requestBody),
-      );
+      ));
       
       if (response.statusCode == 201) {
         return Player.fromJson(json.decode(response.body));
@@ -305,11 +401,11 @@ class ApiService {
       final url = '$baseUrl/players/${player.id}';
       final body = json.encode(requestBody);
       
-      final response = await http.put(
+      final response = await _send(() => http.put(
         Uri.parse(url),
-        headers: {'Content-Type': 'application/json'},
+        headers: _headers(json: true),
         body: body,
-      );
+      ));
       
       if (response.statusCode == 200) {
         return Player.fromJson(json.decode(response.body));
@@ -324,7 +420,7 @@ class ApiService {
 
   static Future<void> deletePlayer(int id) async {
     try {
-      final response = await http.delete(Uri.parse('$baseUrl/players/$id'));
+      final response = await _send(() => http.delete(Uri.parse('$baseUrl/players/$id'), headers: _headers()));
       
       if (response.statusCode != 200 && response.statusCode != 204) {
         throw Exception('Failed to delete player: ${response.statusCode}');
@@ -31,6 +31,8 @@ class AppTheme {
   static const Color pendingColor = Color(0xFF9E9E9E);
   static const Color liveColor = Color(0xFF4CAF50);
   static const Color finishedColor = Color(0xFF2196F3);
+  static const Color confirmationColor = Color(0xFFFFC107);
+  static const Color disputedColor = Color(0xFFF44336);
   static const Color draftColor = Color(0xFFFF9800);
   static const Color finalizedColor = Color(0xFF4CAF50);
   
@@ -17,11 +17,13 @@ class MatchCard extends StatelessWidget {
   Widget build(BuildContext context) {
     final isPlayer1Winner = match.winner == match.player1;
     final isDraw = match.winner == null && match.status == MatchStatus.finished;
-    final statusColor = match.status == MatchStatus.pending
-        ? AppTheme.pendingColor
-        : match.status == MatchStatus.started
-            ? AppTheme.liveColor
-            : AppTheme.finishedColor;
+    final statusColor = switch (match.status) {
+      MatchStatus.pending => AppTheme.pendingColor,
+      MatchStatus.started => AppTheme.liveColor,
+      MatchStatus.pendingConfirmation => AppTheme.confirmationColor,
+      MatchStatus.finished => AppTheme.finishedColor,
+      MatchStatus.disputed => AppTheme.disputedColor,
+    };
     
     return Container(
       margin: const EdgeInsets.symmetric(
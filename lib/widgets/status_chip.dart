@@ -42,11 +42,21 @@ class StatusChip extends StatelessWidget {
           label: 'Live',
           backgroundColor: AppTheme.liveColor,
         );
+      case MatchStatus.pendingConfirmation:
+        return const StatusChip(
+          label: 'Awaiting Confirmation',
+          backgroundColor: AppTheme.confirmationColor,
+        );
       case MatchStatus.finished:
         return const StatusChip(
           label: 'Finished',
           backgroundColor: AppTheme.finishedColor,
         );
+      case MatchStatus.disputed:
+        return const StatusChip(
+          label: 'Disputed',
+          backgroundColor: AppTheme.disputedColor,
+        );
     }
   }
 